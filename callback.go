@@ -0,0 +1,32 @@
+package pixelui
+
+import (
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// callbackTextureID is the first texture id reserved for draw callbacks, far above any atlas id.
+const callbackTextureID = 1 << 30
+
+// AddDrawCallback registers fn to run while Draw renders the current window's draw list, at the
+//
+//	point the callback was added. Use it to draw Pixel sprites inline with the UI. The window's matrix
+//	and compose method are restored for the UI once fn returns.
+func (ui *UI) AddDrawCallback(fn func(win *opengl.Window)) {
+	// imgui-go can't add user callbacks to a draw list, so mark the spot with an empty image using a
+	//	reserved texture id. Changing texture id splits the draw list into its own command for Draw to find.
+	id := imgui.TextureID(callbackTextureID + len(ui.callbacks))
+	ui.callbacks = append(ui.callbacks, fn)
+
+	pos := imgui.CursorScreenPos()
+	imgui.WindowDrawList().AddImage(id, pos, pos)
+}
+
+// drawCallback returns the callback registered for the given texture id, if any.
+func (ui *UI) drawCallback(id imgui.TextureID) (func(win *opengl.Window), bool) {
+	i := int(id) - callbackTextureID
+	if i < 0 || i >= len(ui.callbacks) {
+		return nil, false
+	}
+	return ui.callbacks[i], true
+}
@@ -0,0 +1,9 @@
+//go:build pixelui_bgra
+
+package pixelui
+
+// Channel shifts of imgui's packed colors, for imgui built with IMGUI_USE_BGRA_PACKED_COLOR.
+const (
+	colorShiftR = 16
+	colorShiftB = 0
+)
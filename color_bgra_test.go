@@ -0,0 +1,22 @@
+//go:build pixelui_bgra
+
+package pixelui
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestColorRoundTrip(t *testing.T) {
+	// IMGUI_USE_BGRA_PACKED_COLOR packs blue into the low byte.
+	const packed = 0x80332211
+	if got, want := imguiColorToPixelColor(packed), (color.NRGBA{R: 0x33, G: 0x22, B: 0x11, A: 0x80}); got != want {
+		t.Errorf("imguiColorToPixelColor(%#x) = %v, want %v", packed, got, want)
+	}
+
+	for _, c := range []uint32{packed, 0xff332211, 0x00332211, 0x01ffffff, 0x7f000000} {
+		if got := pixelColorToImguiColor(imguiColorToPixelColor(c)); got != c {
+			t.Errorf("%#x converted to a Pixel color and back = %#x", c, got)
+		}
+	}
+}
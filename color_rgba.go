@@ -0,0 +1,9 @@
+//go:build !pixelui_bgra
+
+package pixelui
+
+// Channel shifts of imgui's packed colors, for the default IM_COL32 layout.
+const (
+	colorShiftR = 0
+	colorShiftB = 16
+)
@@ -0,0 +1,42 @@
+package pixelui
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// maxPayloadType is the longest drag and drop payload type imgui will store.
+const maxPayloadType = 32
+
+// SetDragDropPayload gob-encodes v and sets it as the payload of the current drag and drop source.
+//
+//	Call it between imgui.BeginDragDropSource and imgui.EndDragDropSource.
+func (ui *UI) SetDragDropPayload(payloadType string, v any) error {
+	if len(payloadType) > maxPayloadType {
+		return fmt.Errorf("drag and drop payload type %q is longer than %d bytes", payloadType, maxPayloadType)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return fmt.Errorf("could not encode drag and drop payload: %w", err)
+	}
+
+	imgui.SetDragDropPayload(payloadType, buf.Bytes(), imgui.ConditionAlways)
+	return nil
+}
+
+// AcceptDragDropPayload decodes a dropped payload of the given type into out, which must be a pointer.
+//
+//	Call it between imgui.BeginDragDropTarget and imgui.EndDragDropTarget. It returns false if nothing
+//	of the given type was dropped or the payload doesn't decode into out.
+func (ui *UI) AcceptDragDropPayload(payloadType string, out any) bool {
+	data := imgui.AcceptDragDropPayload(payloadType, 0)
+	if data == nil {
+		return false
+	}
+
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(out) == nil
+}
@@ -0,0 +1,114 @@
+package pixelui
+
+import (
+	"image/color"
+	"unsafe"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// DrawBatch is one imgui draw command decoded into Go slices.
+//
+//	Positions, UVs and Colors hold one entry per vertex, three per triangle, in imgui's coordinates
+//	(top-left origin). Colors aren't premultiplied by their alpha, like imgui's. Commands that are
+//	callbacks only have Callback set.
+type DrawBatch struct {
+	Positions []pixel.Vec
+	UVs       []pixel.Vec
+	Colors    []color.NRGBA
+	ClipRect  pixel.Rect
+	TextureID imgui.TextureID
+	Callback  func(win *opengl.Window)
+}
+
+// DrawDataSlices decodes the draw data of the last imgui.Render into one batch per draw command.
+//
+//	Draw renders these batches; custom renderers can use them instead of walking imgui's buffers.
+//	The batches reuse their memory from call to call, so they are only valid until the next call
+//	(or Draw) and have to be copied to be kept.
+func (ui *UI) DrawDataSlices() []DrawBatch {
+	data := imgui.RenderedDrawData()
+	if !data.Valid() {
+		return nil
+	}
+
+	batches := ui.batches[:0]
+	defer func() { ui.batches = batches }()
+
+	// In each command, there is a vertex buffer that holds all of the vertices to draw;
+	// 	there's also an index buffer which stores the indices into the vertex buffer that should
+	//	be draw together. The vertex buffer is shared between multiple commands.
+	vertexSize, posOffset, uvOffset, colOffset := imgui.VertexBufferLayout()
+	indexSize := imgui.IndexBufferLayout()
+	for _, cmds := range data.CommandLists() {
+		var indexBufferOffset uintptr
+		start, _ := cmds.VertexBuffer()
+		idxStart, _ := cmds.IndexBuffer()
+
+		for _, cmd := range cmds.Commands() {
+			count := cmd.ElementCount()
+
+			if cmd.HasUserCallback() {
+				cmd, cmds := cmd, cmds
+				batches = append(batches, DrawBatch{Callback: func(*opengl.Window) { cmd.CallUserCallback(cmds) }})
+				indexBufferOffset += uintptr(count * indexSize)
+				continue
+			}
+			if fn, ok := ui.drawCallback(cmd.TextureID()); ok {
+				// The callback's placeholder quad is never drawn, skip over its indices.
+				batches = append(batches, DrawBatch{Callback: fn})
+				indexBufferOffset += uintptr(count * indexSize)
+				continue
+			}
+
+			// Reuse the vertex slices of the batch that was in this spot last frame.
+			var batch DrawBatch
+			if len(batches) < cap(batches) {
+				batch = batches[:len(batches)+1][len(batches)]
+			}
+			batch = DrawBatch{
+				Positions: resize(batch.Positions, count),
+				UVs:       resize(batch.UVs, count),
+				Colors:    resize(batch.Colors, count),
+				ClipRect:  imguiRectToPixelRect(cmd.ClipRect()).Norm(),
+				TextureID: cmd.TextureID(),
+			}
+
+			for i := 0; i < count; i++ {
+				idx := unsafe.Pointer(uintptr(idxStart) + indexBufferOffset)
+				index := readIndex(idx, indexSize)
+				ptr := unsafe.Pointer(uintptr(start) + (uintptr(index * vertexSize)))
+				pos := *(*imgui.Vec2)(unsafe.Pointer(uintptr(ptr) + uintptr(posOffset)))
+				uv := *(*imgui.Vec2)(unsafe.Pointer(uintptr(ptr) + uintptr(uvOffset)))
+				col := *(*uint32)(unsafe.Pointer(uintptr(ptr) + uintptr(colOffset)))
+
+				batch.Positions[i] = PV(pos)
+				batch.UVs[i] = PV(uv)
+				batch.Colors[i] = imguiColorToPixelColor(col)
+				indexBufferOffset += uintptr(indexSize)
+			}
+
+			batches = append(batches, batch)
+		}
+	}
+
+	return batches
+}
+
+// resize returns s with length n, reusing its memory if it is large enough.
+func resize[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	return s[:n]
+}
+
+// readIndex reads an index buffer entry, which is 16 or 32 bits wide depending on imgui's ImDrawIdx.
+func readIndex(ptr unsafe.Pointer, size int) int {
+	if size == 4 {
+		return int(*(*uint32)(ptr))
+	}
+	return int(*(*uint16)(ptr))
+}
@@ -0,0 +1,309 @@
+package pixelui
+
+import (
+	"image/color"
+	"math"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+func TestReadIndex(t *testing.T) {
+	small := []uint16{7, 65535}
+	for i, want := range small {
+		if got := readIndex(unsafe.Pointer(&small[i]), 2); got != int(want) {
+			t.Errorf("16 bit index %d = %d, want %d", i, got, want)
+		}
+	}
+	big := []uint32{7, 70000}
+	for i, want := range big {
+		if got := readIndex(unsafe.Pointer(&big[i]), 4); got != int(want) {
+			t.Errorf("32 bit index %d = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestDrawDataSlices(t *testing.T) {
+	ui, _ := newTestUI(t)
+	// imgui hides a new window for its first frame while it measures the contents.
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		imgui.PushStyleColor(imgui.StyleColorWindowBg, imgui.Vec4{X: 1, Y: 1, Z: 1, W: 0.5})
+		imgui.Begin("Test")
+		imgui.Text("Hello")
+		imgui.End()
+		imgui.PopStyleColor()
+		imgui.Render()
+		ui.inFrame = false
+	}
+
+	batches := ui.DrawDataSlices()
+	if len(batches) == 0 {
+		t.Fatal("no batches for a window with text")
+	}
+	background := false
+	for i, batch := range batches {
+		if n := len(batch.Positions); n == 0 || n%3 != 0 || len(batch.UVs) != n || len(batch.Colors) != n {
+			t.Errorf("batch %d has %d positions, %d uvs and %d colors", i, n, len(batch.UVs), len(batch.Colors))
+		}
+		for _, c := range batch.Colors {
+			// The half transparent white background keeps its full color channels.
+			background = background || c == color.NRGBA{R: 255, G: 255, B: 255, A: 128}
+		}
+	}
+	if !background {
+		t.Error("no vertex has the window background's non-premultiplied color")
+	}
+}
+
+func TestScrolledChildClipRect(t *testing.T) {
+	ui, _ := newTestUI(t)
+	var child pixel.Rect
+	renderWindow(ui, func() {
+		imgui.BeginChildV("Child", IV(150, 40), false, 0)
+		imgui.SetScrollY(50)
+		for i := 0; i < 20; i++ {
+			imgui.Text("Line")
+		}
+		min, size := imgui.WindowPos(), imgui.WindowSize()
+		child = pixel.R(float64(min.X), float64(min.Y), float64(min.X+size.X), float64(min.Y+size.Y))
+		imgui.EndChild()
+	})
+
+	// The shader discards what lies outside the batch's clip rect, both in imgui's coordinates. The
+	//	child's lines scrolled out of view are only hidden by that.
+	clipped := 0
+	for _, batch := range ui.DrawDataSlices() {
+		clip := batch.ClipRect
+		if clip.Min.X < child.Min.X || clip.Min.Y < child.Min.Y || clip.Max.X > child.Max.X || clip.Max.Y > child.Max.Y {
+			continue
+		}
+		for _, p := range batch.Positions {
+			if !clip.Contains(p) {
+				clipped++
+			}
+		}
+	}
+	if clipped == 0 {
+		t.Errorf("no vertex of the scrolled child %v lies outside its clip rect", child)
+	}
+}
+
+func TestDrawLinePixel(t *testing.T) {
+	ui, _ := newTestUI(t)
+	red := color.NRGBA{R: 255, A: 255}
+	ui.NewFrame()
+	ui.DrawLinePixel(pixel.V(20, 20), pixel.V(180, 20), red, 2)
+	imgui.Render()
+	ui.inFrame = false
+
+	// Without windows, the line is all there is to draw.
+	var line []pixel.Vec
+	for _, batch := range ui.DrawDataSlices() {
+		for i, c := range batch.Colors {
+			if c == red {
+				line = append(line, batch.Positions[i])
+			}
+		}
+	}
+	if len(line) == 0 {
+		t.Fatal("no triangles for the line")
+	}
+	// Pixel's y = 20 is imgui's y = 80 in a 100 pixel high window, anti-aliasing widens the line a bit.
+	bounds := pixel.R(line[0].X, line[0].Y, line[0].X, line[0].Y)
+	for _, p := range line {
+		bounds = bounds.Union(pixel.R(p.X, p.Y, p.X, p.Y))
+	}
+	if want := pixel.R(20, 79, 180, 81); bounds.Min.Sub(want.Min).Len() > 2 || bounds.Max.Sub(want.Max).Len() > 2 {
+		t.Errorf("the line covers %v in imgui's coordinates, want about %v", bounds, want)
+	}
+}
+
+func TestDrawCallbackBatch(t *testing.T) {
+	ui, _ := newTestUI(t)
+	var ran []int
+	renderWindow(ui, func() {
+		imgui.Text("Before")
+		ui.AddDrawCallback(func(*opengl.Window) { ran = append(ran, 1) })
+		imgui.Text("Between")
+		ui.AddDrawCallback(func(*opengl.Window) { ran = append(ran, 2) })
+	})
+
+	// Draw runs the callbacks in their own batches, in the order they were added after what came before.
+	textBefore := false
+	for _, batch := range ui.DrawDataSlices() {
+		if batch.Callback == nil {
+			textBefore = true
+			continue
+		}
+		if !textBefore {
+			t.Error("a callback batch comes before the window it was added to")
+		}
+		if len(batch.Positions) != 0 {
+			t.Errorf("a callback batch has %d vertices", len(batch.Positions))
+		}
+		batch.Callback(nil)
+	}
+	if !reflect.DeepEqual(ran, []int{1, 2}) {
+		t.Errorf("the callbacks ran as %v, want 1 then 2", ran)
+	}
+}
+
+func BenchmarkDrawDataSlices(b *testing.B) {
+	ui, _ := newTestUI(b)
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		imgui.ShowDemoWindow(nil)
+		imgui.Render()
+		ui.inFrame = false
+	}
+	// Decoding the same draw data again reuses the batches of the last call.
+	ui.DrawDataSlices()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ui.DrawDataSlices()
+	}
+}
+
+func TestDrawBackgroundImage(t *testing.T) {
+	ui, _ := newTestUI(t)
+	img := ui.AddImage(testPicture(16, 8, 1))
+	renderWindow(ui, func() {
+		imgui.Text("In front")
+		ui.DrawBackgroundImage(img)
+	})
+
+	batches := ui.DrawDataSlices()
+	if len(batches) < 2 || batches[0].TextureID != img.ID() {
+		t.Fatalf("the background image isn't the first of %d draw commands", len(batches))
+	}
+	for _, batch := range batches[1:] {
+		if batch.TextureID == img.ID() {
+			t.Error("the background image is drawn again after the window")
+		}
+	}
+
+	// The quad covers the 200x100 display, sampling the whole image.
+	var bounds, uvs pixel.Rect
+	for i, p := range batches[0].Positions {
+		uv := batches[0].UVs[i]
+		if i == 0 {
+			bounds, uvs = pixel.Rect{Min: p, Max: p}, pixel.Rect{Min: uv, Max: uv}
+		}
+		bounds = bounds.Union(pixel.Rect{Min: p, Max: p})
+		uvs = uvs.Union(pixel.Rect{Min: uv, Max: uv})
+	}
+	uv0, uv1 := img.UV()
+	if bounds != pixel.R(0, 0, 200, 100) || uvs != (pixel.Rect{Min: PV(uv0), Max: PV(uv1)}) {
+		t.Errorf("the background quad covers %v sampling %v, want the display sampling %v-%v", bounds, uvs, uv0, uv1)
+	}
+}
+
+func TestClipRounding(t *testing.T) {
+	ui, _ := newTestUI(t)
+	ui.SetClipRounding(6)
+	display := pixel.R(0, 0, 200, 100)
+	for _, tc := range []struct {
+		kind   float64
+		clip   pixel.Rect
+		radius float64
+	}{
+		{0, pixel.R(10, 10, 90, 70), 6},
+		{1, pixel.R(10, 10, 90, 70), 6},
+		// Never more than half the clip rect's smaller side.
+		{1, pixel.R(10, 10, 90, 19), 4},
+		// Clip rects covering the display stay square.
+		{0, display, 0},
+	} {
+		// Decoded like uiShader does.
+		v := ui.clipIntensity(tc.kind, tc.clip)
+		radius := math.Floor(v/2 + 0.25)
+		if kind := v - 2*radius; radius != tc.radius || kind != tc.kind {
+			t.Errorf("clip %v, kind %v: the shader decodes radius %v and kind %v, want %v", tc.clip, tc.kind, radius, kind, tc.radius)
+		}
+	}
+}
+
+func TestClipRoundingDiscardsCorners(t *testing.T) {
+	ui, win := newTestGLUI(t)
+	ui.SetClipRounding(8)
+	var child pixel.Rect
+	// New windows are hidden in their first frame.
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(200, 100))
+		imgui.BeginV("Test", nil, imgui.WindowFlagsNoDecoration)
+		imgui.SetCursorPos(IV(40, 20))
+		imgui.BeginChildV("Child", IV(80, 60), false, 0)
+		min, max := imgui.WindowPos(), imgui.WindowPos().Plus(imgui.WindowSize())
+		child = pixel.Rect{Min: ui.ToPixel(PV(min)), Max: ui.ToPixel(PV(max))}.Norm()
+		// Fill more than the child, only its rounded clip rect shows.
+		imgui.WindowDrawList().AddRectFilled(min.Minus(IV(10, 10)), max.Plus(IV(10, 10)), imgui.PackedColor(0xffff00ff))
+		imgui.EndChild()
+		imgui.End()
+		ui.Draw(win)
+	}
+
+	pic, err := ui.Capture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pixel.PictureDataFromPicture(pic)
+	magenta := func(at pixel.Vec) bool {
+		c := data.Color(at)
+		return c.R > 0.9 && c.G < 0.1 && c.B > 0.9
+	}
+	if !magenta(child.Center()) {
+		t.Fatalf("the child's center at %v isn't filled", child.Center())
+	}
+	for _, corner := range child.Vertices() {
+		// One pixel in from the corner, well outside its 8 pixel rounding.
+		at := corner.Add(child.Center().Sub(corner).Unit().Scaled(1.5))
+		if magenta(at) {
+			t.Errorf("the child's corner at %v isn't discarded", at)
+		}
+	}
+}
+
+func TestPushClipRectPixel(t *testing.T) {
+	ui, _ := newTestUI(t)
+	magenta := imgui.PackedColor(0xffff00ff)
+	renderWindow(ui, func() {
+		ui.PushClipRectPixel(pixel.R(20, 20, 100, 60))
+		imgui.WindowDrawList().AddRectFilled(imgui.Vec2{}, IV(200, 100), magenta)
+		ui.PopClipRect()
+		imgui.Text("Unclipped")
+	})
+
+	// 20 to 60 up from the bottom of the 100 high display is 40 to 80 down from its top.
+	want := pixel.R(20, 40, 100, 80)
+	clipped, after := false, false
+	for _, batch := range ui.DrawDataSlices() {
+		if batch.ClipRect != want {
+			after = after || clipped
+			continue
+		}
+		clipped = true
+		outside := 0
+		for _, p := range batch.Positions {
+			if !batch.ClipRect.Contains(p) {
+				outside++
+			}
+		}
+		if outside == 0 {
+			t.Error("the filled rect lies within the pushed clip rect, nothing is left to discard")
+		}
+	}
+	if !clipped {
+		t.Fatalf("no draw command carries the pushed clip rect %v", want)
+	}
+	if !after {
+		t.Error("the text after PopClipRect is drawn with the pushed clip rect")
+	}
+}
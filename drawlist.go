@@ -0,0 +1,55 @@
+package pixelui
+
+import (
+	"image/color"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// BackgroundDrawList returns the imgui draw list rendered behind all windows
+func (ui *UI) BackgroundDrawList() imgui.DrawList {
+	return imgui.BackgroundDrawList()
+}
+
+// ForegroundDrawList returns the imgui draw list rendered over all windows
+func (ui *UI) ForegroundDrawList() imgui.DrawList {
+	return imgui.ForegroundDrawList()
+}
+
+// DrawLinePixel draws a line between the given Pixel coordinates behind all windows
+func (ui *UI) DrawLinePixel(a, b pixel.Vec, col color.Color, thickness float32) {
+	ui.BackgroundDrawList().AddLineV(IVec(ui.ToImgui(a)), IVec(ui.ToImgui(b)), imgui.PackedColor(pixelColorToImguiColor(col)), thickness)
+}
+
+// DrawRectPixel draws the outline of the given Pixel rectangle behind all windows
+func (ui *UI) DrawRectPixel(r pixel.Rect, col color.Color) {
+	min, max := ui.RectToImgui(r)
+	ui.BackgroundDrawList().AddRect(min, max, imgui.PackedColor(pixelColorToImguiColor(col)))
+}
+
+// DrawRectFilledPixel fills the given Pixel rectangle behind all windows
+func (ui *UI) DrawRectFilledPixel(r pixel.Rect, col color.Color) {
+	min, max := ui.RectToImgui(r)
+	ui.BackgroundDrawList().AddRectFilled(min, max, imgui.PackedColor(pixelColorToImguiColor(col)))
+}
+
+// DrawBackgroundImage stretches the image over the whole display, behind all imgui windows
+func (ui *UI) DrawBackgroundImage(img Image) {
+	ui.BackgroundDrawList().AddImageV(img.id, imgui.Vec2{}, IVec(ui.displaySize()), img.uv0, img.uv1,
+		imgui.PackedColor(pixelColorToImguiColor(color.White)))
+}
+
+// PushClipRectPixel clips what is drawn into the current window after it to the given Pixel rectangle,
+//
+//	intersected with the current clip rect, until PopClipRect. Draw clips each command to it in the
+//	shader, so it applies to custom draw list geometry as well as widgets.
+func (ui *UI) PushClipRectPixel(r pixel.Rect) {
+	min, max := ui.RectToImgui(r)
+	imgui.WindowDrawList().PushClipRectV(min, max, true)
+}
+
+// PopClipRect removes the clip rect pushed last with PushClipRectPixel
+func (ui *UI) PopClipRect() {
+	imgui.WindowDrawList().PopClipRect()
+}
@@ -4,15 +4,22 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"math"
 	"os"
+	"sort"
+	"sync"
 	"unsafe"
 
+	"github.com/gopxl/pixel/v2/ext/atlas"
 	"github.com/inkyblackness/imgui-go/v4"
 )
 
 // loadFont parses the imgui font data and creates a pixel picture from it.
-func (ui *UI) loadFont() {
+func (ui *UI) loadFont() error {
 	f := ui.fonts.TextureDataAlpha8()
+	if f == nil || f.Width == 0 || f.Height == 0 {
+		return fmt.Errorf("pixelui: failed to bake the font atlas")
+	}
 	pic := image.NewRGBA(image.Rect(0, 0, f.Width, f.Height))
 
 	for y := 0; y < f.Height; y++ {
@@ -23,16 +30,197 @@ func (ui *UI) loadFont() {
 		}
 	}
 
-	ui.atlas.Clear(ui.group)
-	ui.font = ui.group.AddImage(pic)
-	ui.atlas.Pack()
+	ui.clearFont()
+	ui.font = ui.addFontImage(pic)
+	ui.alphaTex[ui.font.ID()] = true
+	ui.pack()
 	ui.fonts.SetTextureID(imgui.TextureID(ui.font.ID()))
+	return nil
+}
+
+// clearFont removes the baked font from the atlas before it is baked again.
+func (ui *UI) clearFont() {
+	id := ui.font.ID()
+	if ui.options.Flags&DETERMINISTIC_ATLAS == 0 {
+		ui.atlas.Clear(ui.fontGroup)
+	} else if ui.alphaTex[id] {
+		// The ids of later images are their position in the copies, keep the slot with a placeholder.
+		ui.packed[id] = image.NewRGBA(image.Rect(0, 0, 1, 1))
+	}
+	delete(ui.alphaTex, id)
 }
 
-// loadDefaultFont loads the imgui default font if the user wants it.
-func (ui *UI) loadDefaultFont() {
-	ui.fonts.AddFontDefault()
-	ui.loadFont()
+// addFontImage adds the baked font to the atlas without packing it.
+func (ui *UI) addFontImage(pic image.Image) atlas.TextureId {
+	if ui.options.Flags&DETERMINISTIC_ATLAS != 0 {
+		// A fresh atlas is packed from the copies, the font group isn't kept.
+		return ui.addToGroup(pic)
+	}
+	return ui.fontGroup.AddImage(pic)
+}
+
+// FontTexID returns the imgui texture id of the baked font atlas.
+//
+//	Atlas ids stay the same when the atlas is repacked (adding images, rebuilding fonts), only their
+//	frames move, and Draw looks the frame up every frame, so the id stays valid until fonts are rebuilt.
+func (ui *UI) FontTexID() imgui.TextureID {
+	return imgui.TextureID(ui.font.ID())
+}
+
+// loadDefaultFont loads the imgui default font if the user wants it, at the given size or imgui's own if 0.
+func (ui *UI) loadDefaultFont(size float32) error {
+	ui.addDefaultFont(size)
+	return ui.loadFont()
+}
+
+// addDefaultFont adds the imgui default font without baking the atlas.
+func (ui *UI) addDefaultFont(size float32) {
+	config := ui.fontConfig()
+	defer config.Delete()
+	if size > 0 {
+		config.SetSize(size)
+	}
+	ui.fonts.AddFontDefaultV(config)
+}
+
+// fontConfig returns a new config to add a font with, set up for pixel perfect text if it's enabled.
+//
+//	The caller has to Delete it.
+func (ui *UI) fontConfig() imgui.FontConfig {
+	config := imgui.NewFontConfig()
+	if ui.pixelPerfect {
+		config.SetPixelSnapH(true)
+		config.SetOversampleH(1)
+		config.SetOversampleV(1)
+	}
+	return config
+}
+
+// SetPixelPerfectText bakes the fonts for crisp bitmap-like text: glyphs snapped to whole pixels without
+//
+//	oversampling, the atlas sampled with nearest filtering (see SetTextureSmooth, disabling goes back to
+//	the window's setting, or nearest for a headless UI) and Draw rounding vertex positions to whole
+//	pixels. The fonts are baked again in a new context like Reset, so imgui.Font handles returned
+//	before need to be looked up again and it has to be called outside of a frame.
+func (ui *UI) SetPixelPerfectText(enabled bool) error {
+	if enabled == ui.pixelPerfect {
+		return nil
+	}
+	ui.pixelPerfect = enabled
+	smooth := false
+	if ui.win != nil {
+		smooth = ui.win.Smooth()
+	}
+	ui.SetTextureSmooth(!enabled && smooth)
+
+	return ui.rebakeFonts()
+}
+
+// defaultFontSize is the pixel size imgui bakes its default font at.
+const defaultFontSize = 13
+
+// contentScaleDelay is how many frames a new content scale has to hold before the fonts are re-baked,
+//
+//	so dragging the window across monitors doesn't re-bake them on every frame.
+const contentScaleDelay = 10
+
+// SetContentScaleSource sets a function NewFrame polls for the window's content scale (DPI scale).
+//
+//	When it changes, the fonts are re-baked at their size times the new scale, keeping text sharp on
+//	the new monitor, and imgui's DisplayFramebufferScale is set to it. This invalidates imgui.Font
+//	handles like Reset. Pixel's window doesn't report its content scale, so without a source (the
+//	default) fonts are baked once, e.g. pass GLFW's GetContentScale.
+func (ui *UI) SetContentScaleSource(scale func() float32) {
+	ui.scaleSource = scale
+}
+
+// updateContentScale polls the content scale source and re-bakes the fonts once a new scale has held.
+func (ui *UI) updateContentScale() {
+	if ui.scaleSource == nil {
+		return
+	}
+
+	scale := ui.scaleSource()
+	if scale <= 0 || math.Abs(float64(scale-ui.contentScale)) < 0.01 {
+		ui.scaleFrames = 0
+		return
+	}
+	if scale != ui.pendingScale {
+		ui.pendingScale = scale
+		ui.scaleFrames = 0
+	}
+	ui.scaleFrames++
+	if ui.scaleFrames < contentScaleDelay {
+		return
+	}
+
+	ui.contentScale = scale
+	ui.scaleFrames = 0
+	if err := ui.rebakeFonts(); err != nil && ui.onError != nil {
+		ui.onError(err.Error())
+	}
+}
+
+// fontFile is a font loaded from a file, kept so Reset can load it again.
+type fontFile struct {
+	name   string
+	path   string
+	size   float32
+	ranges GlyphRangePreset
+}
+
+// reloadFonts adds the default font and the fonts loaded from files to a new context's atlas and bakes it.
+func (ui *UI) reloadFonts() error {
+	if ui.options.Flags&NO_DEFAULT_FONT == 0 {
+		size := ui.options.DefaultFontSize
+		if size <= 0 {
+			size = defaultFontSize
+		}
+		ui.addDefaultFont(size * ui.contentScale)
+	}
+	for _, f := range ui.fontFiles {
+		glyphs, err := ui.glyphRanges(f.ranges)
+		if err != nil {
+			return err
+		}
+		config := ui.fontConfig()
+		font := ui.fonts.AddFontFromFileTTFV(f.path, f.size*ui.contentScale, config, glyphs)
+		config.Delete()
+		if f.name != "" {
+			ui.fontNames[f.name] = font
+		}
+	}
+	if ui.options.Flags&NO_DEFAULT_FONT != 0 && len(ui.fontFiles) == 0 {
+		return nil
+	}
+	return ui.loadFont()
+}
+
+// bakeFallbackFont bakes the font atlas if no font was baked yet, e.g. with NO_DEFAULT_FONT. imgui adds
+//
+//	its default font when baking an empty atlas.
+func (ui *UI) bakeFallbackFont() {
+	if ui.alphaTex[ui.font.ID()] {
+		return
+	}
+	if err := ui.loadFont(); err != nil && ui.onError != nil {
+		ui.onError(err.Error())
+	}
+}
+
+// rebakeFonts bakes the fonts again in a fresh imgui context, as imgui-go can't clear a font atlas.
+//
+//	Like Reset, imgui.Font handles returned before need to be looked up again, but the window
+//	settings and the whole style are carried over to the new context.
+func (ui *UI) rebakeFonts() error {
+	layout := imgui.SaveIniSettingsToMemory()
+	style := copyStyle()
+	if err := ui.Reset(); err != nil {
+		return err
+	}
+	imgui.LoadIniSettingsFromMemory(layout)
+	style.restore()
+	return nil
 }
 
 // AddTTFFont loads the given font into imgui.
@@ -40,6 +228,145 @@ func (ui *UI) AddTTFFont(path string, size float32) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		panic(fmt.Sprintf("The font file: %s does not exist", path))
 	}
-	ui.fonts.AddFontFromFileTTF(path, size)
-	ui.loadFont()
+	config := ui.fontConfig()
+	defer config.Delete()
+	ui.fonts.AddFontFromFileTTFV(path, size, config, ui.fonts.GlyphRangesDefault())
+	ui.fontFiles = append(ui.fontFiles, fontFile{"", path, size, GlyphRangeDefault})
+	if err := ui.loadFont(); err != nil {
+		panic(err)
+	}
+}
+
+// GlyphRangePreset selects one of imgui's built-in glyph ranges to bake a font with.
+type GlyphRangePreset int
+
+// Glyph range presets for AddFontFromFileWithRanges:
+//
+//	GlyphRangeDefault: Basic Latin and Latin-1 supplement.
+//	GlyphRangeCyrillic: Default + Cyrillic.
+//	GlyphRangeJapanese: Default + Hiragana, Katakana and common Kanji.
+//	GlyphRangeChineseFull: Default + full set of about 21000 CJK Unified Ideographs.
+//	GlyphRangeChineseSimplifiedCommon: Default + about 2500 common simplified Chinese ideographs.
+//	GlyphRangeKorean: Default + Korean characters.
+//	GlyphRangeThai: Default + Thai characters.
+//	GlyphRangeVietnamese: Default + Vietnamese characters.
+const (
+	GlyphRangeDefault GlyphRangePreset = iota
+	GlyphRangeCyrillic
+	GlyphRangeJapanese
+	GlyphRangeChineseFull
+	GlyphRangeChineseSimplifiedCommon
+	GlyphRangeKorean
+	GlyphRangeThai
+	GlyphRangeVietnamese
+)
+
+// glyphRanges returns the imgui glyph ranges for the given preset.
+func (ui *UI) glyphRanges(preset GlyphRangePreset) (imgui.GlyphRanges, error) {
+	switch preset {
+	case GlyphRangeDefault:
+		return ui.fonts.GlyphRangesDefault(), nil
+	case GlyphRangeCyrillic:
+		return ui.fonts.GlyphRangesCyrillic(), nil
+	case GlyphRangeJapanese:
+		return ui.fonts.GlyphRangesJapanese(), nil
+	case GlyphRangeChineseFull:
+		return ui.fonts.GlyphRangesChineseFull(), nil
+	case GlyphRangeChineseSimplifiedCommon:
+		return ui.fonts.GlyphRangesChineseSimplifiedCommon(), nil
+	case GlyphRangeKorean:
+		return ui.fonts.GlyphRangesKorean(), nil
+	case GlyphRangeThai:
+		return ui.fonts.GlyphRangesThai(), nil
+	case GlyphRangeVietnamese:
+		return vietnameseRanges(), nil
+	}
+	return 0, fmt.Errorf("unknown glyph range preset: %d", preset)
+}
+
+var (
+	vietnameseOnce sync.Once
+	vietnamese     imgui.AllocatedGlyphRanges
+)
+
+// vietnameseRanges returns imgui's Vietnamese glyph ranges, which imgui-go doesn't bind.
+//
+//	They are built once and kept for the life of the program, like imgui's own static tables.
+func vietnameseRanges() imgui.GlyphRanges {
+	vietnameseOnce.Do(func() {
+		var builder imgui.GlyphRangesBuilder
+		builder.Add(0x0020, 0x00FF) // Basic Latin
+		builder.Add(0x0102, 0x0103)
+		builder.Add(0x0110, 0x0111)
+		builder.Add(0x0128, 0x0129)
+		builder.Add(0x0168, 0x0169)
+		builder.Add(0x01A0, 0x01A1)
+		builder.Add(0x01AF, 0x01B0)
+		builder.Add(0x1EA0, 0x1EF9)
+		vietnamese = builder.Build()
+	})
+	return vietnamese.GlyphRanges
+}
+
+// AddFontFromFileWithRanges loads the given font into imgui, baking the glyphs of the given preset.
+func (ui *UI) AddFontFromFileWithRanges(path string, size float32, ranges GlyphRangePreset) (imgui.Font, error) {
+	return ui.addFontFile(fontFile{"", path, size, ranges})
+}
+
+// AddNamedFont loads the given font like AddFontFromFileWithRanges and registers it under name, so it
+//
+//	can be pushed with PushFontByName. Adding a font with a name already in use replaces it.
+func (ui *UI) AddNamedFont(name, path string, size float32, ranges GlyphRangePreset) (imgui.Font, error) {
+	return ui.addFontFile(fontFile{name, path, size, ranges})
+}
+
+// addFontFile loads the font file into imgui and bakes the atlas.
+func (ui *UI) addFontFile(f fontFile) (imgui.Font, error) {
+	path, size, ranges := f.path, f.size, f.ranges
+	if _, err := os.Stat(path); err != nil {
+		return imgui.DefaultFont, fmt.Errorf("the font file: %s could not be read: %w", path, err)
+	}
+
+	glyphs, err := ui.glyphRanges(ranges)
+	if err != nil {
+		return imgui.DefaultFont, err
+	}
+
+	config := ui.fontConfig()
+	defer config.Delete()
+	font := ui.fonts.AddFontFromFileTTFV(path, size, config, glyphs)
+	if font == imgui.DefaultFont {
+		return imgui.DefaultFont, fmt.Errorf("the font file: %s could not be loaded", path)
+	}
+	ui.fontFiles = append(ui.fontFiles, f)
+	if f.name != "" {
+		ui.fontNames[f.name] = font
+	}
+	if err := ui.loadFont(); err != nil {
+		return imgui.DefaultFont, err
+	}
+
+	return font, nil
+}
+
+// PushFontByName makes the font added with AddNamedFont under name the current font, returning false
+//
+//	if there is none. Pop it with imgui.PopFont when it returns true.
+func (ui *UI) PushFontByName(name string) bool {
+	font, ok := ui.fontNames[name]
+	if !ok {
+		return false
+	}
+	imgui.PushFont(font)
+	return true
+}
+
+// Fonts returns the names of the fonts added with AddNamedFont, sorted
+func (ui *UI) Fonts() []string {
+	names := make([]string, 0, len(ui.fontNames))
+	for name := range ui.fontNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,15 @@
+//go:build imguifreetype
+
+package pixelui
+
+// freeType is true if imgui-go rasterizes fonts with FreeType, see the imguifreetype build tag.
+const freeType = true
+
+// SetFreeTypeRasterizer bakes fonts with FreeType using the given builder flags
+//
+//	(imgui.FreeTypeBuilderFlags*). The fonts are baked again in a new context like Reset, so imgui.Font
+//	handles returned before need to be looked up again and it has to be called outside of a frame.
+func (ui *UI) SetFreeTypeRasterizer(flags uint32) error {
+	ui.builderFlags = uint(flags)
+	return ui.rebakeFonts()
+}
@@ -0,0 +1,19 @@
+//go:build imguifreetype
+
+package pixelui
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+func TestSetFreeTypeRasterizer(t *testing.T) {
+	ui, _ := newTestUI(t)
+	if err := ui.SetFreeTypeRasterizer(imgui.FreeTypeBuilderFlagsBold); err != nil {
+		t.Fatal(err)
+	}
+	if got := ui.fonts.FontBuilderFlags(); got != imgui.FreeTypeBuilderFlagsBold {
+		t.Errorf("font builder flags = %#x, want %#x", got, imgui.FreeTypeBuilderFlagsBold)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !imguifreetype
+
+package pixelui
+
+import "fmt"
+
+// freeType is true if imgui-go rasterizes fonts with FreeType, see the imguifreetype build tag.
+const freeType = false
+
+// SetFreeTypeRasterizer bakes fonts with FreeType using the given builder flags.
+//
+//	This build rasterizes with stb_truetype and always returns an error, build with the imguifreetype
+//	tag (and the freetype2 library) to use FreeType.
+func (ui *UI) SetFreeTypeRasterizer(flags uint32) error {
+	return fmt.Errorf("pixelui: the FreeType rasterizer is not compiled into imgui-go, build with the imguifreetype tag")
+}
@@ -0,0 +1,12 @@
+//go:build !imguifreetype
+
+package pixelui
+
+import "testing"
+
+func TestSetFreeTypeRasterizer(t *testing.T) {
+	ui, _ := newTestUI(t)
+	if err := ui.SetFreeTypeRasterizer(0); err == nil {
+		t.Error("SetFreeTypeRasterizer succeeded without FreeType")
+	}
+}
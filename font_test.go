@@ -0,0 +1,227 @@
+package pixelui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestContentScaleRebake(t *testing.T) {
+	ui, _ := newTestUI(t)
+	scale := float32(1)
+	ui.SetContentScaleSource(func() float32 { return scale })
+
+	rebakes := 0
+	context := ui.context
+	frame := func() {
+		ui.NewFrame()
+		ui.DiscardFrame()
+		if ui.context != context {
+			rebakes++
+			context = ui.context
+		}
+	}
+
+	frame()
+	scale = 2
+	for i := 0; i < 3*contentScaleDelay; i++ {
+		frame()
+	}
+	if rebakes != 1 {
+		t.Errorf("the fonts were re-baked %d times for one scale change, want once", rebakes)
+	}
+	if got := ui.io.DisplayFrameBufferScale(); got != (imgui.Vec2{X: 2, Y: 2}) {
+		t.Errorf("display framebuffer scale = %v, want 2", got)
+	}
+}
+
+func TestRebakeKeepsStyle(t *testing.T) {
+	ui, _ := newTestUI(t)
+	style := imgui.CurrentStyle()
+	style.SetAlpha(0.5)
+	style.SetTabRounding(7)
+	style.SetWindowTitleAlign(imgui.Vec2{X: 0.5, Y: 0.5})
+	style.SetAntiAliasedFill(false)
+	style.SetColor(imgui.StyleColorWindowBg, imgui.Vec4{X: 1, Y: 0, Z: 0, W: 1})
+	want := copyStyle()
+
+	if err := ui.rebakeFonts(); err != nil {
+		t.Fatal(err)
+	}
+	if got := copyStyle(); !reflect.DeepEqual(got, want) {
+		t.Errorf("style after re-baking = %+v, want %+v", got, want)
+	}
+}
+
+func TestSetPixelPerfectTextHeadless(t *testing.T) {
+	ui, _ := newTestUI(t)
+	ui.SetTextureSmooth(true)
+	if err := ui.SetPixelPerfectText(true); err != nil {
+		t.Fatal(err)
+	}
+	if ui.smooth {
+		t.Error("the atlas is still smoothed with pixel perfect text")
+	}
+
+	// A headless UI has no window smoothing to go back to.
+	if err := ui.SetPixelPerfectText(false); err != nil {
+		t.Fatal(err)
+	}
+	if ui.smooth || ui.pixelPerfect {
+		t.Errorf("after disabling pixel perfect text smooth = %v, pixel perfect = %v", ui.smooth, ui.pixelPerfect)
+	}
+}
+
+// testFontFile writes the Go Regular font to a temporary file and returns its path.
+func testFontFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Go-Regular.ttf")
+	if err := os.WriteFile(path, goregular.TTF, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCyrillicGlyphRange(t *testing.T) {
+	path := testFontFile(t)
+	ui, _ := newTestUI(t)
+	for _, tt := range []struct {
+		ranges GlyphRangePreset
+		want   bool
+	}{
+		{GlyphRangeDefault, false},
+		{GlyphRangeCyrillic, true},
+	} {
+		font, err := ui.AddFontFromFileWithRanges(path, 16, tt.ranges)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A missing glyph is replaced by the font's fallback glyph.
+		glyph := font.FindGlyph('Ж')
+		if got := glyph.Codepoint() == 'Ж' && glyph.Visible(); got != tt.want {
+			t.Errorf("glyph range preset %d has a glyph for Ж = %v, want %v", tt.ranges, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultFontSize(t *testing.T) {
+	for _, tt := range []struct {
+		size, want float32
+	}{
+		{0, defaultFontSize},
+		{24, 24},
+	} {
+		ui, _ := newTestUIWithOptions(t, Options{Flags: OWN_ATLAS, DefaultFontSize: tt.size})
+		ui.NewFrame()
+		// imgui's text line height is the size of the current font.
+		if got := imgui.TextLineHeight(); got != tt.want {
+			t.Errorf("default font size option %v baked a font of size %v, want %v", tt.size, got, tt.want)
+		}
+		ui.DiscardFrame()
+	}
+}
+
+func TestAlphaTextures(t *testing.T) {
+	ui, _ := newTestUI(t)
+	oldFont := ui.font.ID()
+	font, err := ui.AddFontFromFileWithRanges(testFontFile(t), 16, GlyphRangeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := ui.AddImage(testPicture(8, 8, 1))
+	if ui.alphaTex[oldFont] && oldFont != ui.font.ID() {
+		t.Error("the texture of the font baked before adding a font is still an alpha texture")
+	}
+
+	renderWindow(ui, func() {
+		imgui.Text("Default")
+		imgui.PushFont(font)
+		imgui.Text("Go")
+		imgui.PopFont()
+		ui.Image(img)
+	})
+
+	// Both fonts are baked into the font texture and go through the alpha path, the image doesn't.
+	alpha := make(map[imgui.TextureID]bool)
+	for _, batch := range ui.DrawDataSlices() {
+		alpha[batch.TextureID] = ui.alphaTex[uint32(batch.TextureID)]
+	}
+	want := map[imgui.TextureID]bool{ui.FontTexID(): true, img.ID(): false}
+	if !reflect.DeepEqual(alpha, want) {
+		t.Errorf("textures drawn through the alpha path = %v, want %v", alpha, want)
+	}
+}
+
+func TestFontTexIDAfterRepack(t *testing.T) {
+	ui, _ := newTestUI(t)
+	font := ui.FontTexID()
+	// Large enough to be packed before the font, moving it.
+	img := ui.AddImage(testPicture(255, 255, 1))
+
+	if ui.FontTexID() != font || ui.fonts.TextureID() != font {
+		t.Errorf("font texture id = %v, imgui's = %v after adding an image, want %v", ui.FontTexID(), ui.fonts.TextureID(), font)
+	}
+	renderWindow(ui, func() {
+		imgui.Text("Go")
+		ui.Image(img)
+	})
+	drawn := false
+	for _, batch := range ui.DrawDataSlices() {
+		if batch.TextureID == font {
+			drawn = true
+		}
+	}
+	if !drawn || !ui.alphaTex[uint32(font)] {
+		t.Fatalf("text drawn with the font texture = %v, drawn as alpha only = %v", drawn, ui.alphaTex[uint32(font)])
+	}
+
+	// The font's frame in the repacked atlas still holds the font's coverage, not the image.
+	pic, _ := ui.PictureForTexID(font)
+	covered := false
+	for i, c := range pixel.PictureDataFromPicture(pic).Pix {
+		if c.R != 0 || c.G != 0 || c.B != 0 {
+			t.Fatalf("font texture pixel %d = %v, want alpha only", i, c)
+		}
+		covered = covered || c.A != 0
+	}
+	if !covered {
+		t.Error("the font texture is empty after the repack")
+	}
+}
+
+func TestNamedFonts(t *testing.T) {
+	ui, _ := newTestUI(t)
+	file := testFontFile(t)
+	for name, size := range map[string]float32{"Small": 10, "Large": 24} {
+		if _, err := ui.AddNamedFont(name, file, size, GlyphRangeDefault); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := ui.Fonts(), []string{"Large", "Small"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fonts() = %v, want %v", got, want)
+	}
+
+	sizes := make(map[string]float32)
+	missing := true
+	renderWindow(ui, func() {
+		for _, name := range []string{"Small", "Large"} {
+			if ui.PushFontByName(name) {
+				sizes[name] = imgui.FontSize()
+				imgui.Text(name)
+				imgui.PopFont()
+			}
+		}
+		missing = ui.PushFontByName("Missing")
+	})
+	if want := map[string]float32{"Small": 10, "Large": 24}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("font sizes pushed by name = %v, want %v", sizes, want)
+	}
+	if missing {
+		t.Error("PushFontByName pushed a font that was never added")
+	}
+}
@@ -48,3 +48,31 @@ func UnprojectV(v imgui.Vec2) pixel.Vec {
 func IZV() imgui.Vec2 {
 	return imgui.Vec2{X: 0, Y: 0}
 }
+
+// ToImgui converts a Pixel window coordinate into imgui's (top-left origin) coordinates
+func (ui *UI) ToImgui(p pixel.Vec) pixel.Vec {
+	return ui.inverse.Project(p)
+}
+
+// ToPixel converts an imgui coordinate into Pixel's (bottom-left origin) window coordinates
+func (ui *UI) ToPixel(p pixel.Vec) pixel.Vec {
+	return ui.matrix.Project(p)
+}
+
+// invert returns the inverse of the given affine matrix.
+func invert(m pixel.Matrix) pixel.Matrix {
+	det := m[0]*m[3] - m[2]*m[1]
+	return pixel.Matrix{
+		m[3] / det, -m[1] / det,
+		-m[2] / det, m[0] / det,
+		(m[2]*m[5] - m[3]*m[4]) / det, (m[1]*m[4] - m[0]*m[5]) / det,
+	}
+}
+
+// RectToImgui converts a Pixel rect to its top-left (min) and bottom-right (max) imgui corners
+func (ui *UI) RectToImgui(r pixel.Rect) (min, max imgui.Vec2) {
+	a := ui.ToImgui(r.Min)
+	b := ui.ToImgui(r.Max)
+	n := pixel.Rect{Min: a, Max: b}.Norm()
+	return IVec(n.Min), IVec(n.Max)
+}
@@ -0,0 +1,45 @@
+package pixelui
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gopxl/pixel/v2"
+)
+
+func TestToImguiRoundTrip(t *testing.T) {
+	points := []pixel.Vec{pixel.ZV, pixel.V(30, 40), pixel.V(200, 100), pixel.V(-12.5, 250)}
+	for _, display := range []pixel.Vec{pixel.ZV, pixel.V(320, 180)} {
+		ui, _ := newTestUI(t)
+		ui.SetDisplaySize(display)
+		for _, p := range points {
+			got := ui.ToPixel(ui.ToImgui(p))
+			if math.Abs(got.X-p.X) > 1e-9 || math.Abs(got.Y-p.Y) > 1e-9 {
+				t.Errorf("display %v: ToPixel(ToImgui(%v)) = %v", display, p, got)
+			}
+		}
+	}
+}
+
+func TestToImguiDisplaySize(t *testing.T) {
+	ui, _ := newTestUI(t)
+	// The 200x100 window shows a 400x400 display: imgui's top-left corner is the window's top-left.
+	ui.SetDisplaySize(pixel.V(400, 400))
+	if got, want := ui.ToImgui(pixel.V(0, 100)), pixel.ZV; got != want {
+		t.Errorf("ToImgui(top-left) = %v, want %v", got, want)
+	}
+	if got, want := ui.ToImgui(pixel.V(200, 0)), pixel.V(400, 400); got != want {
+		t.Errorf("ToImgui(bottom-right) = %v, want %v", got, want)
+	}
+}
+
+func TestRectToImgui(t *testing.T) {
+	ui, _ := newTestUI(t)
+	// Pixel's y axis points up from the bottom of the 100 pixel high window, imgui's down from the top.
+	for _, r := range []pixel.Rect{pixel.R(20, 10, 60, 30), pixel.R(60, 30, 20, 10)} {
+		min, max := ui.RectToImgui(r)
+		if min != IV(20, 70) || max != IV(60, 90) {
+			t.Errorf("RectToImgui(%v) = %v-%v, want (20,70)-(60,90)", r, min, max)
+		}
+	}
+}
@@ -3,17 +3,17 @@ module github.com/gopxl/pixelui/v2
 go 1.21
 
 require (
+	github.com/gopxl/glhf/v2 v2.1.0
 	github.com/gopxl/mainthread/v2 v2.1.1
 	github.com/gopxl/pixel/v2 v2.3.0
 	github.com/inkyblackness/imgui-go/v4 v4.7.0
+	golang.org/x/image v0.19.0
 )
 
 require (
 	github.com/go-gl/gl v0.0.0-20231021071112-07e5d0ea2e71 // indirect
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20240506104042-037f3cc74f2a // indirect
 	github.com/go-gl/mathgl v1.1.0 // indirect
-	github.com/gopxl/glhf/v2 v2.1.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	golang.org/x/exp v0.0.0-20240808152545-0cdaa3abc0fa // indirect
-	golang.org/x/image v0.19.0 // indirect
 )
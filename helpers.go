@@ -1,6 +1,10 @@
 package pixelui
 
-import "github.com/inkyblackness/imgui-go/v4"
+import (
+	"image/color"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
 
 // Color converts the given 8-bit r,g,b components to a imgui.Vec4 for color arguments
 func Color(r, g, b uint8) imgui.Vec4 {
@@ -17,3 +21,9 @@ func ColorA(r, g, b, a uint8) imgui.Vec4 {
 		W: float32(a) / scale,
 	}
 }
+
+// IColor converts a Go color to a imgui.Vec4 for color arguments
+func IColor(c color.Color) imgui.Vec4 {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return ColorA(n.R, n.G, n.B, n.A)
+}
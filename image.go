@@ -0,0 +1,205 @@
+package pixelui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/gopxl/glhf/v2"
+	"github.com/gopxl/mainthread/v2"
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// Image is a picture registered with the UI's atlas, ready to be drawn by imgui
+type Image struct {
+	id       imgui.TextureID
+	size     pixel.Vec
+	uv0, uv1 imgui.Vec2
+}
+
+// ID returns the imgui texture id of the image
+func (img Image) ID() imgui.TextureID {
+	return img.id
+}
+
+// Size returns the size of the image in pixels
+func (img Image) Size() pixel.Vec {
+	return img.size
+}
+
+// UV returns the top-left and bottom-right texture coordinates of the image within its texture
+func (img Image) UV() (uv0, uv1 imgui.Vec2) {
+	return img.uv0, img.uv1
+}
+
+// AddImage packs the picture into the UI's atlas and returns a handle to draw it with
+func (ui *UI) AddImage(pic pixel.Picture) Image {
+	img := ui.addImage(pic)
+	ui.pack()
+	return img
+}
+
+// PreloadImages packs all the pictures into the UI's atlas with a single atlas build, e.g. before the
+//
+//	first frame, instead of repacking for every image. The ids are in the order of the pictures.
+func (ui *UI) PreloadImages(pics []pixel.Picture) []imgui.TextureID {
+	ids := make([]imgui.TextureID, len(pics))
+	for i, pic := range pics {
+		ids[i] = ui.addImage(pic).ID()
+	}
+	ui.pack()
+	return ids
+}
+
+// addImage adds the picture to the UI's group without packing the atlas.
+func (ui *UI) addImage(pic pixel.Picture) Image {
+	tex := ui.addToGroup(pixel.PictureDataFromPicture(pic).Image())
+	return Image{
+		id:   imgui.TextureID(tex.ID()),
+		size: pic.Bounds().Size(),
+		uv1:  imgui.Vec2{X: 1, Y: 1},
+	}
+}
+
+// AddSpriteSheet packs the picture into the UI's atlas as a single entry and returns one image per
+//
+//	cell of the given size, row by row from the top-left. The images share the texture id and only
+//	differ in their texture coordinates. Cells that don't fit completely are left out.
+func (ui *UI) AddSpriteSheet(pic pixel.Picture, cell pixel.Vec) []Image {
+	if cell.X <= 0 || cell.Y <= 0 {
+		return nil
+	}
+	sheet := ui.AddImage(pic)
+
+	cols, rows := int(sheet.size.X/cell.X), int(sheet.size.Y/cell.Y)
+	step := imgui.Vec2{X: float32(cell.X / sheet.size.X), Y: float32(cell.Y / sheet.size.Y)}
+	images := make([]Image, 0, cols*rows)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			uv0 := imgui.Vec2{X: float32(x) * step.X, Y: float32(y) * step.Y}
+			images = append(images, Image{
+				id:   sheet.id,
+				size: cell,
+				uv0:  uv0,
+				uv1:  imgui.Vec2{X: uv0.X + step.X, Y: uv0.Y + step.Y},
+			})
+		}
+	}
+	return images
+}
+
+// Image draws the image at its own size
+func (ui *UI) Image(img Image) {
+	imgui.ImageV(img.id, IVec(img.size), img.uv0, img.uv1, imgui.Vec4{X: 1, Y: 1, Z: 1, W: 1}, imgui.Vec4{})
+}
+
+// ImageRotated draws the image scaled to size and rotated counter-clockwise by angle (in radians)
+//
+//	around its center. It takes up size in the layout; corners sticking out are clipped to the window.
+func (ui *UI) ImageRotated(img Image, size pixel.Vec, angle float64) {
+	center := PV(imgui.CursorScreenPos()).Add(size.Scaled(0.5))
+	imgui.Dummy(IVec(size))
+
+	// imgui's y axis points down, so rotating clockwise there turns counter-clockwise on screen.
+	half := size.Scaled(0.5)
+	corner := func(x, y float64) imgui.Vec2 {
+		return IVec(center.Add(pixel.V(x*half.X, y*half.Y).Rotated(-angle)))
+	}
+	imgui.WindowDrawList().AddImageQuadV(img.id,
+		corner(-1, -1), corner(1, -1), corner(1, 1), corner(-1, 1),
+		img.uv0, imgui.Vec2{X: img.uv1.X, Y: img.uv0.Y}, img.uv1, imgui.Vec2{X: img.uv0.X, Y: img.uv1.Y},
+		imgui.PackedColor(pixelColorToImguiColor(color.White)))
+}
+
+// ImageButton draws the image as a button, returning true when it is clicked
+func (ui *UI) ImageButton(id string, img Image) bool {
+	imgui.PushID(id)
+	defer imgui.PopID()
+	return imgui.ImageButtonV(img.id, IVec(img.size), img.uv0, img.uv1, -1, imgui.Vec4{}, imgui.Vec4{X: 1, Y: 1, Z: 1, W: 1})
+}
+
+// PictureForTexID returns a copy of the pixels of an image registered with the UI, so it can be drawn
+//
+//	with Pixel's sprites. It returns false if the id isn't in the UI's atlas.
+func (ui *UI) PictureForTexID(id imgui.TextureID) (pixel.Picture, bool) {
+	frame, ok := ui.textureFrame(id)
+	if !ok {
+		return nil, false
+	}
+
+	tex := ui.atlas.Textures()[ui.pageOf(uint32(id))]
+	pic := pixel.MakePictureData(pixel.R(0, 0, frame.W(), frame.H()))
+	for y := 0; y < int(frame.H()); y++ {
+		for x := 0; x < int(frame.W()); x++ {
+			at := pixel.V(float64(x), float64(y))
+			pic.Pix[pic.Index(at)] = tex.Pix[tex.Index(frame.Min.Add(at))]
+		}
+	}
+	return pic, true
+}
+
+// textureFrame returns the frame of the texture in the atlas, or false if the atlas doesn't know it.
+//
+//	The atlas flips its frames upside down, the frame is normalised to the bottom-up coordinates of
+//	the atlas' PictureData.
+func (ui *UI) textureFrame(id imgui.TextureID) (frame pixel.Rect, ok bool) {
+	// The atlas panics on unknown ids and has no way to ask first.
+	defer func() {
+		if recover() != nil {
+			frame, ok = pixel.ZR, false
+		}
+	}()
+	return ui.atlas.Get(uint32(id)).Frame().Norm(), true
+}
+
+// UpdateImage replaces the pixels of a registered image in place, uploading only its region of the
+//
+//	atlas texture instead of re-packing. The picture must be the same size as the image.
+func (ui *UI) UpdateImage(id imgui.TextureID, pic pixel.Picture) error {
+	frame, ok := ui.textureFrame(id)
+	if !ok {
+		return fmt.Errorf("texture id %d isn't in the UI's atlas", id)
+	}
+	data := pixel.PictureDataFromPicture(pic)
+	if data.Bounds().Size() != frame.Size() {
+		return fmt.Errorf("picture size %v doesn't match the image size %v", data.Bounds().Size(), frame.Size())
+	}
+
+	page := ui.pageOf(uint32(id))
+	var tex pixel.Picture = ui.atlas.Textures()[page]
+	// The PictureData and the GL texture both store rows bottom-up, from the frame's Min.
+	origin := frame.Min.Sub(tex.Bounds().Min)
+	x, y := int(origin.X), int(origin.Y)
+	w, h := int(frame.W()), int(frame.H())
+
+	// Keep the atlas' copy in sync so the update survives the picture being re-created.
+	if pd, ok := tex.(*pixel.PictureData); ok {
+		for row := 0; row < h; row++ {
+			copy(pd.Pix[(y+row)*pd.Stride+x:], data.Pix[row*data.Stride:row*data.Stride+w])
+		}
+	}
+	if ui.options.Flags&DETERMINISTIC_ATLAS != 0 {
+		ui.packed[id] = data.Image()
+	}
+
+	if ui.win == nil {
+		// A headless UI has no texture to upload to.
+		return nil
+	}
+	gl, ok := ui.atlasPicture(ui.win, page).(interface{ Texture() *glhf.Texture })
+	if !ok {
+		return fmt.Errorf("the atlas picture has no texture to update")
+	}
+
+	pixels := make([]uint8, 0, w*h*4)
+	for _, c := range data.Pix {
+		pixels = append(pixels, c.R, c.G, c.B, c.A)
+	}
+
+	mainthread.Call(func() {
+		gl.Texture().Begin()
+		gl.Texture().SetPixels(x, y, w, h, pixels)
+		gl.Texture().End()
+	})
+	return nil
+}
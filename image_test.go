@@ -0,0 +1,206 @@
+package pixelui
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// testPicture returns a picture of the given size whose every pixel is different.
+func testPicture(w, h int, blue uint8) *pixel.PictureData {
+	pic := pixel.MakePictureData(pixel.R(0, 0, float64(w), float64(h)))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pic.Pix[y*pic.Stride+x] = color.RGBA{R: uint8(x), G: uint8(y), B: blue, A: 255}
+		}
+	}
+	return pic
+}
+
+// samePixels reports the first pixel where the pictures differ.
+func samePixels(t *testing.T, got pixel.Picture, want *pixel.PictureData) {
+	t.Helper()
+	data := pixel.PictureDataFromPicture(got)
+	if data.Bounds().Size() != want.Bounds().Size() {
+		t.Fatalf("picture size = %v, want %v", data.Bounds().Size(), want.Bounds().Size())
+	}
+	for i := range want.Pix {
+		if data.Pix[i] != want.Pix[i] {
+			t.Fatalf("pixel %d = %v, want %v", i, data.Pix[i], want.Pix[i])
+		}
+	}
+}
+
+func TestPictureForTexID(t *testing.T) {
+	ui, _ := newTestUI(t)
+	want := testPicture(16, 8, 1)
+	img := ui.AddImage(want)
+
+	got, ok := ui.PictureForTexID(img.ID())
+	if !ok {
+		t.Fatal("PictureForTexID doesn't know the image")
+	}
+	samePixels(t, got, want)
+}
+
+func TestUpdateImage(t *testing.T) {
+	ui, _ := newTestUI(t)
+	first := testPicture(16, 8, 1)
+	ids := ui.PreloadImages([]pixel.Picture{first, testPicture(12, 6, 2)})
+	if frame, _ := ui.textureFrame(ids[1]); frame.Min == pixel.ZV {
+		t.Fatalf("the updated image is at the atlas' origin, frame %v", frame)
+	}
+
+	want := testPicture(12, 6, 3)
+	if err := ui.UpdateImage(ids[1], want); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := ui.PictureForTexID(ids[1])
+	samePixels(t, got, want)
+	// The neighbouring image is untouched.
+	got, _ = ui.PictureForTexID(ids[0])
+	samePixels(t, got, first)
+
+	if err := ui.UpdateImage(ids[1], testPicture(16, 8, 3)); err == nil {
+		t.Error("UpdateImage accepted a picture of the wrong size")
+	}
+}
+
+func TestSpriteSheetDrawData(t *testing.T) {
+	ui, _ := newTestUI(t)
+	sheet := testPicture(32, 32, 1)
+	cells := ui.AddSpriteSheet(sheet, pixel.V(8, 8))
+	if len(cells) != 16 {
+		t.Fatalf("a 4x4 sheet has %d cells", len(cells))
+	}
+	// Cells go row by row from the top-left, the second row's second cell.
+	cell := cells[5]
+	if uv0, uv1 := cell.UV(); uv0 != IV(0.25, 0.25) || uv1 != IV(0.5, 0.5) {
+		t.Errorf("cell 5 has texture coordinates %v-%v, want (0.25,0.25)-(0.5,0.5)", uv0, uv1)
+	}
+
+	renderWindow(ui, func() {
+		ui.Image(cell)
+	})
+
+	// Draw maps the texture coordinates into the image's frame in the atlas texture, the quad has to
+	//	cover exactly the cell's pixels. PictureData rows go bottom-up, so the second row from the top
+	//	covers y 16 to 24 of the sheet.
+	frame := ui.atlas.Get(uint32(cell.ID())).Frame()
+	want := pixel.R(8, 16, 16, 24).Moved(frame.Norm().Min)
+	var got pixel.Rect
+	for _, batch := range ui.DrawDataSlices() {
+		if batch.TextureID != cell.ID() {
+			continue
+		}
+		for i, uv := range batch.UVs {
+			p := ui.calcData(frame, uv, pixel.V(1, 1))
+			if i == 0 {
+				got = pixel.Rect{Min: p, Max: p}
+			}
+			got = got.Union(pixel.Rect{Min: p, Max: p})
+		}
+	}
+	if got != want {
+		t.Fatalf("the cell's quad samples the atlas at %v, want %v", got, want)
+	}
+	// The atlas has the sheet's pixel there: red is x and green is y.
+	if c := ui.atlas.Textures()[0].Color(want.Min.Add(pixel.V(0.5, 0.5))); c.R*255 != 8 || c.G*255 != 16 {
+		t.Errorf("the atlas has %v at the cell's corner, want the sheet's pixel (8, 16)", c)
+	}
+}
+
+func TestImageDrawnTwice(t *testing.T) {
+	ui, _ := newTestUI(t)
+	img := ui.AddImage(testPicture(16, 8, 1))
+	if img.Size() != pixel.V(16, 8) {
+		t.Errorf("image size = %v, want 16x8", img.Size())
+	}
+
+	renderWindow(ui, func() {
+		ui.Image(img)
+		ui.Image(img)
+	})
+
+	vertices := 0
+	for _, batch := range ui.DrawDataSlices() {
+		if batch.TextureID == img.ID() {
+			vertices += len(batch.Positions)
+		}
+	}
+	// Each image is a quad of two triangles.
+	if vertices != 12 {
+		t.Errorf("the image texture has %d vertices, want two quads", vertices)
+	}
+}
+
+func TestPreloadImages(t *testing.T) {
+	ui, _ := newTestUI(t)
+	pics := make([]pixel.Picture, 10)
+	for i := range pics {
+		pics[i] = testPicture(8+i, 16-i, uint8(i))
+	}
+	ids := ui.PreloadImages(pics)
+	if len(ids) != len(pics) {
+		t.Fatalf("%d ids for %d pictures", len(ids), len(pics))
+	}
+
+	seen := make(map[imgui.TextureID]bool)
+	for i, id := range ids {
+		if seen[id] {
+			t.Errorf("image %d has the id %v of an earlier image", i, id)
+		}
+		seen[id] = true
+		got, ok := ui.PictureForTexID(id)
+		if !ok {
+			t.Fatalf("image %d isn't in the atlas", i)
+		}
+		samePixels(t, got, pics[i].(*pixel.PictureData))
+		// Small images all fit on the UI's first texture page, next to the font.
+		if page := ui.pageOf(uint32(id)); page != 0 {
+			t.Errorf("image %d is on texture page %d", i, page)
+		}
+	}
+}
+
+func TestImageRotated(t *testing.T) {
+	ui, _ := newTestUI(t)
+	img := ui.AddImage(testPicture(16, 8, 1))
+	var center pixel.Vec
+	renderWindow(ui, func() {
+		center = PV(imgui.CursorScreenPos()).Add(pixel.V(16, 8))
+		ui.ImageRotated(img, pixel.V(32, 16), math.Pi/2)
+	})
+
+	uv0, uv1 := img.UV()
+	// A quarter turn counter-clockwise on screen takes the image's top-left corner to the bottom-left,
+	//	below the center in imgui's coordinates.
+	want := map[pixel.Vec]pixel.Vec{
+		PV(uv0):                                 center.Add(pixel.V(-8, 16)),
+		pixel.V(float64(uv1.X), float64(uv0.Y)): center.Add(pixel.V(-8, -16)),
+		PV(uv1):                                 center.Add(pixel.V(8, -16)),
+		pixel.V(float64(uv0.X), float64(uv1.Y)): center.Add(pixel.V(8, 16)),
+	}
+	vertices := 0
+	for _, batch := range ui.DrawDataSlices() {
+		if batch.TextureID != img.ID() {
+			continue
+		}
+		for i, uv := range batch.UVs {
+			vertices++
+			corner, ok := want[uv]
+			if !ok {
+				t.Fatalf("vertex %d has texture coordinates %v, not one of the image's corners", i, uv)
+			}
+			if d := batch.Positions[i].Sub(corner); d.Len() > 1e-3 {
+				t.Errorf("the corner at %v is at %v, want %v", uv, batch.Positions[i], corner)
+			}
+		}
+	}
+	if vertices != 6 {
+		t.Errorf("the rotated image has %d vertices, want one quad", vertices)
+	}
+}
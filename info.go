@@ -0,0 +1,29 @@
+package pixelui
+
+import "github.com/inkyblackness/imgui-go/v4"
+
+// BackendInfo describes the imgui build pixelui is running against, useful in bug reports
+type BackendInfo struct {
+	// Version is the Dear ImGui version string
+	Version string
+	// Docking is true if imgui was built from the docking branch
+	Docking bool
+	// Viewports is true if multi-viewport support is available
+	Viewports bool
+	// IndexSize is the size in bytes of ImDrawIdx, 2 or 4
+	IndexSize int
+	// FreeType is true if fonts are rasterized with FreeType instead of stb_truetype
+	FreeType bool
+}
+
+// BackendInfo returns information about the imgui build.
+//
+//	imgui-go wraps the non-docking branch, so Docking and Viewports are always false for now. FreeType
+//	is true when built with the imguifreetype tag.
+func (ui *UI) BackendInfo() BackendInfo {
+	return BackendInfo{
+		Version:   imgui.Version(),
+		IndexSize: imgui.IndexBufferLayout(),
+		FreeType:  freeType,
+	}
+}
@@ -0,0 +1,17 @@
+package pixelui
+
+import "testing"
+
+func TestBackendInfo(t *testing.T) {
+	ui, _ := newTestUI(t)
+	info := ui.BackendInfo()
+	if info.Version == "" {
+		t.Error("BackendInfo has no imgui version")
+	}
+	if info.IndexSize != 2 && info.IndexSize != 4 {
+		t.Errorf("index size = %d, want 2 or 4", info.IndexSize)
+	}
+	if info.FreeType != freeType {
+		t.Errorf("FreeType = %v, want %v for this build", info.FreeType, freeType)
+	}
+}
@@ -1,6 +1,11 @@
 package pixelui
 
 import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
 	"github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/inkyblackness/imgui-go/v4"
@@ -19,29 +24,74 @@ func (c Clipboard) SetText(value string) {
 	c.win.SetClipboardText(value)
 }
 
+// funcClipboard is an imgui clipboard backed by user functions instead of the window.
+type funcClipboard struct {
+	get func() string
+	set func(string)
+}
+
+func (c funcClipboard) Text() (string, error) {
+	if c.get == nil {
+		return "", nil
+	}
+	return c.get(), nil
+}
+
+func (c funcClipboard) SetText(value string) {
+	if c.set != nil {
+		c.set(value)
+	}
+}
+
+// SetClipboardHandler makes imgui copy and paste through the given functions instead of the window's clipboard.
+//
+//	Passing nil for both restores the window's clipboard.
+func (ui *UI) SetClipboardHandler(get func() string, set func(string)) {
+	if get == nil && set == nil && ui.win != nil {
+		ui.clipboard = Clipboard{win: ui.win}
+	} else {
+		ui.clipboard = funcClipboard{get: get, set: set}
+	}
+	ui.io.SetClipboard(ui.clipboard)
+}
+
+// GetImage returns the image on the clipboard, if any.
+//
+//	Pixel's window clipboard only carries text, so this currently always reports false.
+func (c Clipboard) GetImage() (pixel.Picture, bool) {
+	return nil, false
+}
+
+// SetImage places the image on the clipboard where the platform supports it; otherwise it does nothing.
+func (c Clipboard) SetImage(pic pixel.Picture) {
+}
+
+// ClipboardImage returns the image on the window's clipboard and whether one was available
+func (ui *UI) ClipboardImage() (pixel.Picture, bool) {
+	return Clipboard{win: ui.win}.GetImage()
+}
+
 func (ui *UI) initIO() {
-	ui.io.SetDisplaySize(IVec(ui.win.Bounds().Size()))
-	ui.io.SetClipboard(Clipboard{win: ui.win})
+	ui.io.SetDisplaySize(IVec(ui.displaySize()))
+	ui.io.SetDisplayFrameBufferScale(imgui.Vec2{X: ui.contentScale, Y: ui.contentScale})
+	if ui.clipboard == nil {
+		ui.clipboard = Clipboard{win: ui.win}
+	}
+	ui.io.SetClipboard(ui.clipboard)
 
 	for k, v := range keyMap {
 		ui.io.KeyMap(v, int(k))
 	}
 
-	ui.win.SetButtonCallback(func(win *opengl.Window, button pixel.Button, action pixel.Action) {
-		if !button.IsKeyboardButton() {
-			return
-		}
-
-		switch action {
-		case pixel.Press:
-			ui.io.KeyPress(int(button))
-		case pixel.Release:
-			ui.io.KeyRelease(int(button))
-		}
-	})
+	ui.input.SetButtonCallback(ui.buttonCallback)
 
 	ui.io.SetBackendFlags(imgui.BackendFlagsHasMouseCursors | imgui.BackendFlagsHasSetMousePos)
+	ui.io.SetIniFilename(ui.iniFilename)
+	ui.io.SetMouseDrawCursor(ui.softCursor || ui.softFallback && ui.fallbackVisible)
 
+	if ui.win == nil {
+		return
+	}
 	ui.cursors[imgui.MouseCursorArrow] = opengl.CreateStandardCursor(opengl.ArrowCursor)
 	ui.cursors[imgui.MouseCursorTextInput] = opengl.CreateStandardCursor(opengl.IBeamCursor)
 	ui.cursors[imgui.MouseCursorHand] = opengl.CreateStandardCursor(opengl.HandCursor)
@@ -49,29 +99,217 @@ func (ui *UI) initIO() {
 	ui.cursors[imgui.MouseCursorResizeNS] = opengl.CreateStandardCursor(opengl.VResizeCursor)
 }
 
+// maxQueuedEvents bounds the button events kept between frames, e.g. while NewFrame isn't called.
+const maxQueuedEvents = 1024
+
+// eventQueue buffers button events from the input source until prepareIO hands them to imgui.
+type eventQueue struct {
+	mu     sync.Mutex
+	events []buttonEvent
+}
+
+// push adds an event, dropping the oldest one if the queue is full.
+func (q *eventQueue) push(e buttonEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.events) >= maxQueuedEvents {
+		q.events = append(q.events[:0], q.events[1:]...)
+	}
+	q.events = append(q.events, e)
+}
+
+// drain moves the queued events into buf, in the order they arrived.
+func (q *eventQueue) drain(buf []buttonEvent) []buttonEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	buf = append(buf[:0], q.events...)
+	q.events = q.events[:0]
+	return buf
+}
+
+// buttonCallback queues button events from the input source for the next prepareIO.
+func (ui *UI) buttonCallback(button pixel.Button, action pixel.Action) {
+	if !button.IsKeyboardButton() && ui.unhandled == nil {
+		return
+	}
+	ui.events.push(buttonEvent{button, action})
+}
+
+// forwardKeys hands the queued keyboard events to imgui, and the events imgui didn't capture to the
+//
+//	unhandled input callback. Mouse buttons are only queued for the callback, imgui polls them.
+func (ui *UI) forwardKeys() {
+	ui.drained = ui.events.drain(ui.drained)
+	for _, e := range ui.drained {
+		if ui.unhandled != nil && !ui.inputWant(e.Button) {
+			ui.unhandled(InputEvent{Button: e.Button, Action: e.Action, Mouse: ui.input.MousePosition()})
+		}
+		if !e.Button.IsKeyboardButton() || !ui.captureKeyboard {
+			continue
+		}
+		ui.keyEvent = true
+		switch e.Action {
+		case pixel.Press:
+			ui.io.KeyPress(int(e.Button))
+		case pixel.Release:
+			ui.io.KeyRelease(int(e.Button))
+		}
+	}
+}
+
 // prepareIO tells imgui.io about our current io state.
 func (ui *UI) prepareIO() {
-	ui.io.SetDisplaySize(IVec(ui.win.Bounds().Size()))
+	// Re-baking the fonts switches to a new context, do it before filling in this frame's io.
+	ui.updateContentScale()
+	ui.io.SetDisplaySize(IVec(ui.displaySize()))
 
-	ui.io.AddMouseWheelDelta(float32(ui.win.MouseScroll().X), float32(ui.win.MouseScroll().Y))
-	mouse := ui.matrix.Unproject(ui.win.MousePosition())
-	ui.io.SetMousePosition(imgui.Vec2{X: float32(mouse.X), Y: float32(mouse.Y)})
+	scroll := ui.input.MouseScroll().ScaledXY(ui.scrollSpeed)
+	ui.io.AddMouseWheelDelta(float32(scroll.X), float32(scroll.Y))
+	if ui.unhandled != nil && scroll != pixel.ZV && !ui.wantMouse() {
+		ui.unhandled(InputEvent{Scroll: ui.input.MouseScroll(), Mouse: ui.input.MousePosition()})
+	}
+	// A held button keeps the mouse position coming past the window's edge, like imgui_impl_glfw, so
+	//	slider and window drags aren't cancelled there.
+	if ui.mouseInside() || ui.mouseButtonDown() {
+		mouse := ui.ToImgui(ui.inputMatrix.Project(ui.input.MousePosition()))
+		ui.io.SetMousePosition(imgui.Vec2{X: float32(mouse.X), Y: float32(mouse.Y)})
+	} else {
+		// imgui's convention for "no mouse available"
+		ui.io.SetMousePosition(imgui.Vec2{X: -math.MaxFloat32, Y: -math.MaxFloat32})
+	}
 
-	ui.io.SetMouseButtonDown(0, ui.win.Pressed(pixel.MouseButtonLeft))
-	ui.io.SetMouseButtonDown(1, ui.win.Pressed(pixel.MouseButtonRight))
-	ui.io.SetMouseButtonDown(2, ui.win.Pressed(pixel.MouseButtonMiddle))
+	ui.io.SetMouseButtonDown(0, ui.captureMouse && ui.input.Pressed(pixel.MouseButtonLeft))
+	ui.io.SetMouseButtonDown(1, ui.captureMouse && ui.input.Pressed(pixel.MouseButtonRight))
+	ui.io.SetMouseButtonDown(2, ui.captureMouse && ui.input.Pressed(pixel.MouseButtonMiddle))
 
-	ui.io.AddInputCharacters(ui.win.Typed())
+	ui.forwardKeys()
+
+	// Characters only go to imgui while a text field is active, everywhere else the key events
+	//	(sent from buttonCallback) drive widgets and shortcuts, so space/enter aren't handled twice.
+	typed := ui.input.Typed()
+	if typed != "" && ui.captureKeyboard && ui.io.WantTextInput() {
+		ui.io.AddInputCharacters(textInput(typed))
+	}
 	ui.updateKeyMod()
+	ui.updateIdle(scroll, typed)
+}
+
+// InputEvent is a mouse or keyboard event imgui didn't capture, see SetUnhandledInputCallback.
+//
+//	Button events set Button and Action, scroll events set Scroll instead. Mouse is the mouse position
+//	in window coordinates when the event was handed over.
+type InputEvent struct {
+	Button pixel.Button
+	Action pixel.Action
+	Scroll pixel.Vec
+	Mouse  pixel.Vec
+}
 
+// IsScroll returns true if the event is a mouse wheel scroll rather than a button event
+func (e InputEvent) IsScroll() bool {
+	return e.Scroll != pixel.ZV
+}
+
+// SetUnhandledInputCallback calls fn from NewFrame for every button press, release and repeat and
+//
+//	every scroll since the last frame that imgui didn't want, in the order they happened, as a single
+//	place to handle game input instead of checking JustPressed for each button. nil removes it.
+func (ui *UI) SetUnhandledInputCallback(fn func(ev InputEvent)) {
+	ui.unhandled = fn
+}
+
+// updateCursor shows the OS cursor imgui asked for. Called from Draw after imgui.Render, once imgui
+//
+//	has settled on the cursor for this frame, instead of applying the previous frame's choice.
+func (ui *UI) updateCursor() {
+	if ui.softCursor {
+		return
+	}
 	c, has := ui.cursors[imgui.MouseCursor()]
+	if ui.cursorFallback {
+		// imgui draws its software cursor while rendering, so this shows from the next frame on.
+		ui.setSoftFallback(!has)
+		if !has {
+			return
+		}
+	}
 	if !has {
 		c = ui.cursors[imgui.MouseCursorArrow]
 	}
-	ui.win.SetCursor(c)
+	if ui.win != nil {
+		ui.win.SetCursor(c)
+	}
+}
+
+// setSoftFallback switches between the OS cursor and imgui's software cursor for the fallback. The OS
+//
+//	cursor's visibility is only changed when switching, and restored to what it was before, so a
+//	cursor the app hid stays hidden (and imgui doesn't draw one in its place).
+func (ui *UI) setSoftFallback(soft bool) {
+	if soft == ui.softFallback {
+		return
+	}
+	ui.softFallback = soft
+	if soft {
+		ui.fallbackVisible = ui.win == nil || ui.win.CursorVisible()
+		ui.io.SetMouseDrawCursor(ui.fallbackVisible)
+		ui.setCursorVisible(false)
+		return
+	}
+	ui.io.SetMouseDrawCursor(false)
+	ui.setCursorVisible(ui.fallbackVisible)
+}
+
+// SetSoftwareCursorFallback makes imgui draw its software cursor only for the shapes the OS cursor
+//
+//	can't show (e.g. diagonal resize), instead of falling back to the arrow. The OS cursor is used
+//	for every other shape.
+func (ui *UI) SetSoftwareCursorFallback(enabled bool) {
+	ui.cursorFallback = enabled
+	if !enabled && !ui.softCursor {
+		ui.setSoftFallback(false)
+	}
+}
+
+// SetMouseDrawCursor makes imgui draw its own (software) cursor and hides the OS cursor, or switches back.
+func (ui *UI) SetMouseDrawCursor(enabled bool) {
+	ui.softCursor = enabled
+	ui.io.SetMouseDrawCursor(enabled)
+	ui.setCursorVisible(!enabled)
+}
+
+// setCursorVisible shows or hides the OS cursor, a headless UI has none.
+func (ui *UI) setCursorVisible(visible bool) {
+	if ui.win != nil {
+		ui.win.SetCursorVisible(visible)
+	}
+}
+
+// textInput strips control characters, which imgui receives as key events instead.
+func textInput(typed string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, typed)
+}
+
+// mouseInside returns true if the mouse is over the window
+func (ui *UI) mouseInside() bool {
+	return sourceMouseInside(ui.input) && ui.bounds().Contains(ui.input.MousePosition())
+}
+
+// mouseButtonDown returns true if any of the mouse buttons imgui uses is held
+func (ui *UI) mouseButtonDown() bool {
+	return ui.input.Pressed(pixel.MouseButtonLeft) || ui.input.Pressed(pixel.MouseButtonRight) || ui.input.Pressed(pixel.MouseButtonMiddle)
 }
 
 // updateKeyMod tells imgui.io where to find our key modifiers
+//
+//	imgui-go only binds the legacy io.KeyCtrl/KeyShift/... modifier state, there is no AddKeyEvent
+//	for ImGuiMod_* events. The modifiers are derived from the key down state we already forward,
+//	so chords like Ctrl+C still reach imgui.
 func (ui *UI) updateKeyMod() {
 	ui.io.KeyCtrl(int(pixel.KeyLeftControl), int(pixel.KeyRightControl))
 	ui.io.KeyShift(int(pixel.KeyLeftShift), int(pixel.KeyRightShift))
@@ -83,60 +321,147 @@ func (ui *UI) updateKeyMod() {
 func (ui *UI) inputWant(button pixel.Button) bool {
 	switch button {
 	case pixel.MouseButton1, pixel.MouseButton2, pixel.MouseButton3, pixel.MouseButton4, pixel.MouseButton5, pixel.MouseButton6, pixel.MouseButton7, pixel.MouseButton8:
-		return ui.io.WantCaptureMouse()
+		return ui.wantMouse()
+	}
+	return ui.wantKeyboard()
+}
+
+// wantMouse returns true if imgui wants the mouse and mouse capture is enabled
+func (ui *UI) wantMouse() bool {
+	if ui.captureByHover {
+		return ui.captureMouse && ui.mouseOverWindow
 	}
-	return ui.io.WantCaptureKeyboard()
+	return ui.captureMouse && ui.io.WantCaptureMouse()
+}
+
+// IsMouseOverAnyWindow returns true if the mouse is over an imgui window this frame.
+//
+//	Unlike imgui's WantCaptureMouse, this is false while the mouse is between windows, even if a
+//	window is focused or was clicked last.
+func (ui *UI) IsMouseOverAnyWindow() bool {
+	return ui.mouseOverWindow
+}
+
+// SetMouseCaptureByHover selects what keeps mouse input from the game: with it enabled, only the
+//
+//	mouse being over an imgui window (IsMouseOverAnyWindow), otherwise imgui's WantCaptureMouse,
+//	the default.
+func (ui *UI) SetMouseCaptureByHover(enabled bool) {
+	ui.captureByHover = enabled
+}
+
+// wantKeyboard returns true if imgui wants the keyboard and keyboard capture is enabled
+func (ui *UI) wantKeyboard() bool {
+	return ui.captureKeyboard && ui.io.WantCaptureKeyboard()
+}
+
+// SetScrollSpeed scales the mouse scroll forwarded to imgui, e.g. to tame high resolution trackpads.
+//
+//	Defaults to 1, 1.
+func (ui *UI) SetScrollSpeed(x, y float32) {
+	ui.scrollSpeed = pixel.V(float64(x), float64(y))
+}
+
+// SetInputMatrix sets the matrix mapping window coordinates to the coordinates the UI is drawn in,
+//
+//	e.g. when the UI is drawn into a canvas that is then scaled onto the window. Defaults to pixel.IM.
+func (ui *UI) SetInputMatrix(m pixel.Matrix) {
+	ui.inputMatrix = m
+}
+
+// SetCaptureMask selects which input channels imgui may capture from the game.
+//
+//	With mouse capture disabled, mouse buttons are not forwarded to imgui and always reach the game.
+//	With keyboard capture disabled, keys and typed text are not forwarded to imgui either.
+func (ui *UI) SetCaptureMask(mouse, keyboard bool) {
+	ui.captureMouse = mouse
+	ui.captureKeyboard = keyboard
 }
 
 // MouseScroll returns the mouse scroll amount if imgui does not want the mouse
 //
 //	(if mouse is not hovering an imgui element)
 func (ui *UI) MouseScroll() pixel.Vec {
-	if ui.io.WantCaptureMouse() {
+	if ui.wantMouse() {
 		return pixel.ZV
 	}
 
-	return ui.win.MouseScroll()
+	return ui.input.MouseScroll()
+}
+
+// WorldMouse returns the world position under the mouse for a camera matrix that maps world to window
+//
+//	coordinates, e.g. the one set with win.SetMatrix. It returns false if imgui wants the mouse or the
+//	mouse is outside the window.
+func (ui *UI) WorldMouse(cam pixel.Matrix) (pixel.Vec, bool) {
+	if ui.wantMouse() || !ui.mouseInside() {
+		return pixel.ZV, false
+	}
+	return cam.Unproject(ui.input.MousePosition()), true
+}
+
+// IsAnyItemHovered returns true if the mouse is over imgui, i.e. imgui wants the mouse and it is over
+//
+//	one of imgui's windows. imgui-go doesn't bind ImGui::IsAnyItemHovered, so this includes a window's
+//	empty space.
+func (ui *UI) IsAnyItemHovered() bool {
+	return ui.io.WantCaptureMouse() && imgui.IsWindowHoveredV(imgui.HoveredFlagsAnyWindow)
+}
+
+// IsAnyItemActive returns true if any imgui item is active (e.g. a button being held)
+func (ui *UI) IsAnyItemActive() bool {
+	return imgui.IsAnyItemActive()
+}
+
+// HoveredRect returns the bounds of the last item in Pixel coordinates if it is hovered
+func (ui *UI) HoveredRect() (pixel.Rect, bool) {
+	if !imgui.IsItemHovered() {
+		return pixel.ZR, false
+	}
+
+	min := ui.ToPixel(PV(imgui.ItemRectMin()))
+	max := ui.ToPixel(PV(imgui.ItemRectMax()))
+	return pixel.Rect{Min: min, Max: max}.Norm(), true
 }
 
 // JustPressed returns true if imgui hasn't handled the button and the button was just pressed
 func (ui *UI) JustPressed(button pixel.Button) bool {
-	return !ui.inputWant(button) && ui.win.JustPressed(button)
+	return !ui.inputWant(button) && ui.input.JustPressed(button)
 }
 
 // JustPressed returns true if imgui hasn't handled the button and the button was just released
 func (ui *UI) JustReleased(button pixel.Button) bool {
-	return !ui.inputWant(button) && ui.win.JustReleased(button)
+	return !ui.inputWant(button) && ui.input.JustReleased(button)
 }
 
 // JustPressed returns true if imgui hasn't handled the button and the button is pressed
 func (ui *UI) Pressed(button pixel.Button) bool {
-	return !ui.inputWant(button) && ui.win.Pressed(button)
+	return !ui.inputWant(button) && ui.input.Pressed(button)
 }
 
 // Repeated returns true if imgui hasn't handled the button and the button was repeated
 func (ui *UI) Repeated(button pixel.Button) bool {
-	return !ui.inputWant(button) && ui.win.Repeated(button)
+	return !ui.inputWant(button) && ui.input.Repeated(button)
 }
 
 // KeyCtrl returns true if either left or right control is pressed
 func (ui *UI) KeyCtrl() bool {
-	return ui.win.Pressed(pixel.KeyLeftControl) || ui.win.Pressed(pixel.KeyRightControl)
+	return ui.input.Pressed(pixel.KeyLeftControl) || ui.input.Pressed(pixel.KeyRightControl)
 }
 
 // KeyCtrl returns true if either left or right shift is pressed
 func (ui *UI) KeyShift() bool {
-	return ui.win.Pressed(pixel.KeyLeftShift) || ui.win.Pressed(pixel.KeyRightShift)
+	return ui.input.Pressed(pixel.KeyLeftShift) || ui.input.Pressed(pixel.KeyRightShift)
 }
 
 // KeyCtrl returns true if either left or right alt is pressed
 func (ui *UI) KeyAlt() bool {
-	return ui.win.Pressed(pixel.KeyLeftAlt) || ui.win.Pressed(pixel.KeyRightAlt)
+	return ui.input.Pressed(pixel.KeyLeftAlt) || ui.input.Pressed(pixel.KeyRightAlt)
 }
 
 // KeyCtrl returns true if either left or right super (windows key) is pressed
 func (ui *UI) KeySuper() bool {
-	return ui.win.Pressed(pixel.KeyLeftSuper) || ui.win.Pressed(pixel.KeyRightSuper)
+	return ui.input.Pressed(pixel.KeyLeftSuper) || ui.input.Pressed(pixel.KeyRightSuper)
 }
 
 var (
@@ -164,3 +489,17 @@ var (
 		pixel.KeyZ:         imgui.KeyZ,
 	}
 )
+
+// IsTextInputActive returns true while a text field is being edited, unlike WantCaptureKeyboard which
+//
+//	is also true for keyboard navigation and other widgets using keys.
+func (ui *UI) IsTextInputActive() bool {
+	return ui.io.WantTextInput()
+}
+
+// ClearActiveID drops the keyboard focus from the active widget, e.g. a text field in a closed modal,
+//
+//	so game keys reach the game again.
+func (ui *UI) ClearActiveID() {
+	imgui.ClearActiveID()
+}
@@ -0,0 +1,737 @@
+package pixelui
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+func TestDefaultMousePosition(t *testing.T) {
+	ui, input := newTestUI(t)
+	input.MoveMouse(pixel.V(30, 40))
+	ui.NewFrame()
+	defer ui.DiscardFrame()
+
+	// imgui's y axis points down from the top of the 100 pixel high window.
+	if got, want := ui.io.MousePosition(), IV(30, 60); got != want {
+		t.Errorf("mouse position = %v, want %v", got, want)
+	}
+}
+
+func TestDefaultScrollSpeed(t *testing.T) {
+	ui, input := newTestUI(t)
+	input.Scroll(pixel.V(1, -2))
+	ui.NewFrame()
+	defer ui.DiscardFrame()
+
+	if x, y := ui.io.MouseWheel(); x != 1 || y != -2 {
+		t.Errorf("mouse wheel = %v, %v, want 1, -2", x, y)
+	}
+}
+
+func TestKeyboardCapture(t *testing.T) {
+	for _, capture := range []bool{true, false} {
+		ui, input := newTestUI(t)
+		ui.SetCaptureMask(true, capture)
+		input.Press(pixel.KeyA)
+		ui.NewFrame()
+		if got := imgui.IsKeyDown(int(pixel.KeyA)); got != capture {
+			t.Errorf("with keyboard capture %v, imgui sees the key down = %v", capture, got)
+		}
+		ui.DiscardFrame()
+	}
+}
+
+func TestMouseOutsideWindow(t *testing.T) {
+	ui, input := newTestUI(t)
+	input.MoveMouse(pixel.V(250, 40))
+	ui.NewFrame()
+	if got := ui.io.MousePosition(); got.X != -math.MaxFloat32 {
+		t.Errorf("mouse position outside the window = %v, want no mouse", got)
+	}
+
+	// A held button keeps the position coming, so drags continue past the edge.
+	input.Press(pixel.MouseButtonLeft)
+	ui.NewFrame()
+	defer ui.DiscardFrame()
+	if got, want := ui.io.MousePosition(), IV(250, 60); got != want {
+		t.Errorf("mouse position while dragging = %v, want %v", got, want)
+	}
+}
+
+func TestEventQueueOrder(t *testing.T) {
+	var q eventQueue
+	want := []buttonEvent{
+		{pixel.KeyA, pixel.Press},
+		{pixel.KeyB, pixel.Press},
+		{pixel.KeyA, pixel.Release},
+		{pixel.KeyB, pixel.Release},
+	}
+	for _, e := range want {
+		q.push(e)
+	}
+	got := q.drain(nil)
+	if len(got) != len(want) {
+		t.Fatalf("drained %d events, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if got := q.drain(got); len(got) != 0 {
+		t.Errorf("second drain returned %d events, want none", len(got))
+	}
+}
+
+func TestEventQueueFull(t *testing.T) {
+	var q eventQueue
+	for i := 0; i < maxQueuedEvents+2; i++ {
+		q.push(buttonEvent{pixel.Button(i), pixel.Press})
+	}
+	got := q.drain(nil)
+	if len(got) != maxQueuedEvents {
+		t.Fatalf("drained %d events, want %d", len(got), maxQueuedEvents)
+	}
+	// The two oldest events are dropped, the rest keep their order.
+	for i, e := range got {
+		if e.Button != pixel.Button(i+2) {
+			t.Fatalf("event %d is button %v, want %v", i, e.Button, pixel.Button(i+2))
+		}
+	}
+}
+
+func TestClearActiveID(t *testing.T) {
+	ui, _ := newTestUI(t)
+	text := ""
+	frame := func(focus bool) {
+		ui.NewFrame()
+		imgui.Begin("Test")
+		if focus {
+			imgui.SetKeyboardFocusHere()
+		}
+		imgui.InputText("Name", &text)
+		imgui.End()
+	}
+	for i := 0; i < 3; i++ {
+		frame(true)
+	}
+	// The focus request applies in the frame after it is made.
+	frame(false)
+	if !imgui.IsAnyItemActive() {
+		t.Fatal("the focused text field isn't active")
+	}
+
+	ui.ClearActiveID()
+	frame(false)
+	defer ui.DiscardFrame()
+	if imgui.IsAnyItemActive() {
+		t.Error("the text field is still active after ClearActiveID")
+	}
+}
+
+func TestKeyboardNavigation(t *testing.T) {
+	ui, input := newTestUIWithOptions(t, Options{Flags: OWN_ATLAS | NAV_KEYBOARD})
+	first, second := "", ""
+	var active string
+	frame := func(focus int) {
+		ui.NewFrame()
+		active = ""
+		ui.Begin("Fields")
+		if focus >= 0 {
+			ui.SetKeyboardFocusHere(focus)
+		}
+		imgui.InputText("First", &first)
+		if imgui.IsItemActive() {
+			active = "First"
+		}
+		imgui.InputText("Second", &second)
+		if imgui.IsItemActive() {
+			active = "Second"
+		}
+		imgui.End()
+		ui.Begin("Other")
+		imgui.End()
+		ui.DiscardFrame()
+		input.Update()
+	}
+
+	// New windows take the focus as they appear, the one begun last keeps it.
+	frame(-1)
+	ui.FocusWindow("Fields")
+	frame(-1)
+	if active != "" {
+		t.Fatalf("%q is active before tabbing into the window", active)
+	}
+
+	// Tab moves the keyboard focus into the focused window's first field.
+	input.Press(pixel.KeyTab)
+	frame(-1)
+	input.Release(pixel.KeyTab)
+	frame(-1)
+	if active != "First" {
+		t.Fatalf("after tab the focused field is %q, want First", active)
+	}
+
+	// The focus request applies in the next frame.
+	frame(1)
+	frame(-1)
+	if active != "Second" {
+		t.Errorf("after focusing the field after the next one, the focused field is %q, want Second", active)
+	}
+
+	// FocusWindow brings the other window to the front, taking the keyboard from the field.
+	ui.FocusWindow("Other")
+	frame(-1)
+	frame(-1)
+	if active != "" {
+		t.Errorf("after focusing the other window %q is still active", active)
+	}
+}
+
+func TestSoftwareCursorFallback(t *testing.T) {
+	ui, input := newTestUI(t)
+	ui.cursors[imgui.MouseCursorArrow] = &opengl.Cursor{}
+	ui.SetSoftwareCursorFallback(true)
+	input.MoveMouse(pixel.V(50, 50))
+
+	// frame renders a frame without windows asking for the cursor, returning whether imgui drew its own.
+	frame := func(cursor imgui.MouseCursorID) bool {
+		ui.NewFrame()
+		imgui.SetMouseCursor(cursor)
+		imgui.Render()
+		ui.inFrame = false
+		drawn := ui.MetricsRenderVertices() > 0
+		ui.updateCursor()
+		return drawn
+	}
+
+	frame(imgui.MouseCursorArrow)
+	frame(imgui.MouseCursorResizeNESW)
+	if !ui.softFallback {
+		t.Fatal("the software cursor isn't used for a shape the OS lacks")
+	}
+	// imgui draws the software cursor from the frame after it was asked for.
+	if !frame(imgui.MouseCursorArrow) {
+		t.Error("the software cursor isn't drawn for the diagonal resize cursor")
+	}
+	if ui.softFallback {
+		t.Error("the software cursor is still used for the arrow")
+	}
+	if frame(imgui.MouseCursorArrow) {
+		t.Error("the software cursor is still drawn for the arrow")
+	}
+}
+
+func TestKeyRepeatDefaults(t *testing.T) {
+	ui, input := newTestUI(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ui.SetTimeSource(func() time.Time {
+		now = now.Add(10 * time.Millisecond)
+		return now
+	})
+	ui.NewFrame()
+	ui.DiscardFrame()
+
+	// Hold the key for half a second, noting when imgui reports it pressed.
+	input.Press(pixel.KeyA)
+	var pressed []int
+	for ms := 0; ms < 500; ms += 10 {
+		ui.NewFrame()
+		if imgui.IsKeyPressedV(int(pixel.KeyA), true) {
+			pressed = append(pressed, ms)
+		}
+		ui.DiscardFrame()
+		input.Update()
+	}
+
+	// imgui's 0.275s repeat delay and 0.05s rate, rounded up to the 10 ms frames.
+	if want := []int{0, 280, 330, 380, 430, 480}; !reflect.DeepEqual(pressed, want) {
+		t.Errorf("the held key was pressed at %v ms, want %v", pressed, want)
+	}
+}
+
+func TestMouseDefaults(t *testing.T) {
+	ui, input := newTestUI(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ui.SetTimeSource(func() time.Time {
+		now = now.Add(100 * time.Millisecond)
+		return now
+	})
+	var dragging, doubleClicked bool
+	frame := func() {
+		ui.NewFrame()
+		// A negative threshold uses io.MouseDragThreshold.
+		dragging = imgui.IsMouseDragging(0, -1)
+		doubleClicked = imgui.IsMouseDoubleClicked(0)
+		ui.DiscardFrame()
+		input.Update()
+	}
+	click := func() {
+		input.Press(pixel.MouseButtonLeft)
+		frame()
+		input.Release(pixel.MouseButtonLeft)
+		frame()
+	}
+
+	// imgui's 6 pixel drag threshold.
+	input.MoveMouse(pixel.V(150, 50))
+	frame()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	input.MoveMouse(pixel.V(154, 50))
+	frame()
+	if dragging {
+		t.Error("moving 4 pixels with the button held started a drag")
+	}
+	input.MoveMouse(pixel.V(160, 50))
+	frame()
+	if !dragging {
+		t.Error("moving 10 pixels with the button held didn't start a drag")
+	}
+	input.Release(pixel.MouseButtonLeft)
+	frame()
+
+	// imgui's 0.3s double-click time: clicks 200 ms apart are a double click, 400 ms apart aren't.
+	input.MoveMouse(pixel.V(150, 50))
+	click()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	if !doubleClicked {
+		t.Error("two clicks 200 ms apart aren't a double click")
+	}
+	input.Release(pixel.MouseButtonLeft)
+	frame()
+	frame()
+	click()
+	frame()
+	frame()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	if doubleClicked {
+		t.Error("two clicks 400 ms apart are a double click")
+	}
+}
+
+func TestResizeCursor(t *testing.T) {
+	for _, tc := range []struct {
+		mouse pixel.Vec
+		want  imgui.MouseCursorID
+	}{
+		// The window's right edge, then its bottom edge.
+		{pixel.V(100, 70), imgui.MouseCursorResizeEW},
+		{pixel.V(50, 40), imgui.MouseCursorResizeNS},
+	} {
+		ui, input := newTestUI(t)
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		ui.SetTimeSource(func() time.Time {
+			now = now.Add(50 * time.Millisecond)
+			return now
+		})
+		frame := func() imgui.MouseCursorID {
+			ui.NewFrame()
+			imgui.SetNextWindowPos(imgui.Vec2{})
+			imgui.SetNextWindowSize(IV(100, 60))
+			imgui.Begin("Test")
+			imgui.End()
+			imgui.Render()
+			ui.inFrame = false
+			ui.updateCursor()
+			input.Update()
+			return imgui.MouseCursor()
+		}
+
+		input.MoveMouse(pixel.V(50, 70))
+		frame()
+		frame()
+		// imgui waits 40 ms before it shows an edge can be dragged, then Draw shows the cursor imgui
+		//	settled on in that same frame.
+		input.MoveMouse(tc.mouse)
+		frame()
+		if got := frame(); got != tc.want {
+			t.Errorf("mouse at %v: cursor = %v, want %v", tc.mouse, got, tc.want)
+		}
+	}
+}
+
+func TestSetMouseDrawCursor(t *testing.T) {
+	ui, input := newTestUI(t)
+	ui.SetSoftwareCursorFallback(true)
+	input.MoveMouse(pixel.V(50, 50))
+
+	// frame renders a frame without windows asking for a cursor the OS lacks, returning whether imgui
+	//	drew its own.
+	frame := func() bool {
+		ui.NewFrame()
+		imgui.SetMouseCursor(imgui.MouseCursorResizeNESW)
+		imgui.Render()
+		ui.inFrame = false
+		ui.updateCursor()
+		return ui.MetricsRenderVertices() > 0
+	}
+
+	ui.SetMouseDrawCursor(true)
+	if !frame() {
+		t.Error("the software cursor isn't drawn")
+	}
+	// The OS cursor is left alone while imgui draws the cursor, the fallback never kicks in.
+	if ui.softFallback {
+		t.Error("the OS cursor was handled with the software cursor enabled")
+	}
+
+	ui.SetMouseDrawCursor(false)
+	ui.SetSoftwareCursorFallback(false)
+	if frame() {
+		t.Error("the software cursor is still drawn after disabling it")
+	}
+}
+
+// textField renders frames of a window with a text field bound to text, focusing it in the first
+//
+//	frames. Key presses made between calls reach imgui in the next frame.
+type textField struct {
+	ui     *UI
+	input  *TestInput
+	text   *string
+	frames int
+}
+
+func (f *textField) frame() {
+	f.ui.NewFrame()
+	imgui.Begin("Test")
+	if f.frames < 3 {
+		imgui.SetKeyboardFocusHere()
+	}
+	f.frames++
+	imgui.InputText("Field", f.text)
+	imgui.End()
+	f.ui.DiscardFrame()
+	f.input.Update()
+}
+
+// chord presses and releases key with ctrl held, a frame each.
+func (f *textField) chord(key pixel.Button) {
+	f.input.Press(pixel.KeyLeftControl)
+	f.input.Press(key)
+	f.frame()
+	f.input.Release(key)
+	f.input.Release(pixel.KeyLeftControl)
+	f.frame()
+}
+
+func TestClipboardHandler(t *testing.T) {
+	ui, input := newTestUI(t)
+	clipboard := ""
+	ui.SetClipboardHandler(func() string { return clipboard }, func(s string) { clipboard = s })
+
+	text := "hello"
+	field := &textField{ui: ui, input: input, text: &text}
+	for i := 0; i < 4; i++ {
+		field.frame()
+	}
+
+	field.chord(pixel.KeyA)
+	field.chord(pixel.KeyC)
+	if clipboard != "hello" {
+		t.Errorf("copying put %q on the clipboard, want %q", clipboard, "hello")
+	}
+
+	clipboard = "pasted"
+	field.chord(pixel.KeyA)
+	field.chord(pixel.KeyV)
+	if text != "pasted" {
+		t.Errorf("pasting left %q in the field, want %q", text, "pasted")
+	}
+}
+
+func TestClipboardImage(t *testing.T) {
+	ui, _ := newTestUI(t)
+	want := testPicture(4, 2, 1)
+	clipboard := Clipboard{win: ui.win}
+	clipboard.SetImage(want)
+
+	got, ok := clipboard.GetImage()
+	if !ok {
+		// Pixel's clipboard only carries text, the image is dropped without an error.
+		if got != nil {
+			t.Errorf("GetImage returned %v without an image on the clipboard", got)
+		}
+		if _, ok := ui.ClipboardImage(); ok {
+			t.Error("ClipboardImage has an image the clipboard doesn't")
+		}
+		return
+	}
+	samePixels(t, got, want)
+}
+
+func TestTypedSpace(t *testing.T) {
+	ui, input := newTestUI(t)
+	text := ""
+	field := &textField{ui: ui, input: input, text: &text}
+	// imgui reports that it wants the keyboard from the frame after the field became active.
+	for i := 0; i < 5; i++ {
+		field.frame()
+	}
+
+	// space presses the key and types the character, like a window does.
+	space := func() (shortcut bool) {
+		input.Press(pixel.KeySpace)
+		input.Type(" ")
+		shortcut = ui.JustPressed(pixel.KeySpace)
+		field.frame()
+		input.Release(pixel.KeySpace)
+		field.frame()
+		return shortcut
+	}
+
+	if space() {
+		t.Error("space typed into the text field also reached the application")
+	}
+	if text != " " {
+		t.Errorf("typing a space into the text field left %q, want one space", text)
+	}
+
+	ui.ClearActiveID()
+	field.frame()
+	if !space() {
+		t.Error("space didn't reach the application without an active text field")
+	}
+	if text != " " {
+		t.Errorf("space reached the inactive text field, it holds %q", text)
+	}
+}
+
+func TestCtrlShortcut(t *testing.T) {
+	ui, input := newTestUI(t)
+	shortcut := func() bool {
+		ui.NewFrame()
+		defer ui.DiscardFrame()
+		input.Update()
+		return ui.io.KeyCtrlPressed() && imgui.IsKeyPressed(int(pixel.KeyC))
+	}
+
+	input.Press(pixel.KeyC)
+	if shortcut() {
+		t.Error("C without ctrl is reported as ctrl+C")
+	}
+	input.Release(pixel.KeyC)
+	shortcut()
+
+	input.Press(pixel.KeyLeftControl)
+	input.Press(pixel.KeyC)
+	if !shortcut() {
+		t.Error("ctrl+C isn't reported")
+	}
+	// The shortcut fires once, not on every frame C is held.
+	if shortcut() {
+		t.Error("ctrl+C is reported again while held")
+	}
+}
+
+func TestWorldMouse(t *testing.T) {
+	ui, input := newTestUI(t)
+	// The camera shows the world at twice the size, scrolled 100 units to the right.
+	cam := pixel.IM.Moved(pixel.V(-100, 0)).Scaled(pixel.ZV, 2)
+
+	input.MoveMouse(pixel.V(150, 80))
+	frame := func() {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(100, 100))
+		imgui.Begin("Test")
+		imgui.End()
+		ui.DiscardFrame()
+	}
+	frame()
+	frame()
+	if got, ok := ui.WorldMouse(cam); !ok || got != pixel.V(175, 40) {
+		t.Errorf("WorldMouse = %v, %v, want (175, 40), true", got, ok)
+	}
+
+	// Over the window imgui has the mouse.
+	input.MoveMouse(pixel.V(50, 50))
+	frame()
+	if _, ok := ui.WorldMouse(cam); ok {
+		t.Error("WorldMouse reports the mouse over an imgui window")
+	}
+}
+
+func TestHoveredRect(t *testing.T) {
+	ui, input := newTestUI(t)
+	// Just below the window's title bar, where its first button is.
+	mouse := pixel.V(20, 65)
+	input.MoveMouse(mouse)
+
+	var rect pixel.Rect
+	var hovered, anyHovered bool
+	renderWindow(ui, func() {
+		imgui.Button("Button")
+		rect, hovered = ui.HoveredRect()
+		anyHovered = ui.IsAnyItemHovered()
+	})
+
+	if !hovered || !anyHovered {
+		t.Fatalf("hovered = %v, any item hovered = %v, want both true", hovered, anyHovered)
+	}
+	if !rect.Contains(mouse) {
+		t.Errorf("hovered rect %v doesn't contain the mouse at %v", rect, mouse)
+	}
+	if ui.IsAnyItemActive() {
+		t.Error("an item is active without a button pressed")
+	}
+}
+
+func TestDragDropPayload(t *testing.T) {
+	type item struct {
+		Name  string
+		Count int
+	}
+	ui, input := newTestUI(t)
+	want := item{"Sword", 3}
+	var (
+		got               item
+		accepted, wrongly bool
+		setErr            error
+	)
+	frame := func() {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(200, 100))
+		imgui.Begin("Test")
+		imgui.Button("Source")
+		if imgui.BeginDragDropSource(0) {
+			setErr = ui.SetDragDropPayload("item", want)
+			imgui.EndDragDropSource()
+		}
+		imgui.Button("Target")
+		if imgui.BeginDragDropTarget() {
+			var n int
+			wrongly = wrongly || ui.AcceptDragDropPayload("item", &n)
+			accepted = accepted || ui.AcceptDragDropPayload("item", &got)
+			imgui.EndDragDropTarget()
+		}
+		imgui.End()
+		ui.DiscardFrame()
+		input.Update()
+	}
+
+	// The source button is just below the title bar, the target one below it.
+	input.MoveMouse(pixel.V(20, 64))
+	frame()
+	frame()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	input.MoveMouse(pixel.V(20, 40))
+	frame()
+	frame()
+	input.Release(pixel.MouseButtonLeft)
+	frame()
+
+	if setErr != nil {
+		t.Fatal(setErr)
+	}
+	if !accepted || got != want {
+		t.Errorf("dropped %+v, accepted = %v, want %+v", got, accepted, want)
+	}
+	if wrongly {
+		t.Error("the payload decoded into an int")
+	}
+	if err := ui.SetDragDropPayload(strings.Repeat("x", maxPayloadType+1), want); err == nil {
+		t.Error("SetDragDropPayload accepted a type imgui can't store")
+	}
+}
+
+func TestMouseCaptureByHover(t *testing.T) {
+	ui, input := newTestUI(t)
+	ui.SetMouseCaptureByHover(true)
+	var over, clicked bool
+	frame := func() {
+		ui.NewFrame()
+		over, clicked = ui.IsMouseOverAnyWindow(), ui.JustPressed(pixel.MouseButtonLeft)
+		// Two windows down the left and right edges, the game shows between them.
+		for _, x := range []float64{0, 140} {
+			imgui.SetNextWindowPos(IV(x, 0))
+			imgui.SetNextWindowSize(IV(60, 100))
+			imgui.Begin(fmt.Sprint("Overlay ", x))
+			imgui.End()
+		}
+		ui.DiscardFrame()
+		input.Update()
+	}
+	// Focus the left window, then click between the windows and on the right one.
+	input.MoveMouse(pixel.V(30, 50))
+	frame()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	input.Release(pixel.MouseButtonLeft)
+	frame()
+
+	input.MoveMouse(pixel.V(100, 50))
+	frame()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	if over || !clicked {
+		t.Errorf("between the windows: over a window = %v, click reached the game = %v", over, clicked)
+	}
+	input.Release(pixel.MouseButtonLeft)
+	frame()
+
+	input.MoveMouse(pixel.V(170, 50))
+	frame()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	if !over || clicked {
+		t.Errorf("on the right window: over a window = %v, click reached the game = %v", over, clicked)
+	}
+}
+
+func TestUnhandledInputCallback(t *testing.T) {
+	ui, input := newTestUI(t)
+	var events []InputEvent
+	ui.SetUnhandledInputCallback(func(ev InputEvent) {
+		events = append(events, ev)
+	})
+	frame := func() {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(100, 100))
+		imgui.Begin("Left")
+		imgui.End()
+		ui.DiscardFrame()
+		input.Update()
+	}
+	click := func(at pixel.Vec) {
+		input.MoveMouse(at)
+		frame()
+		input.Press(pixel.MouseButtonLeft)
+		frame()
+		input.Release(pixel.MouseButtonLeft)
+		frame()
+	}
+
+	// The window covers the left half, a click there is imgui's once it is shown.
+	frame()
+	click(pixel.V(50, 50))
+	if len(events) != 0 {
+		t.Fatalf("a click on the window was handed over: %+v", events)
+	}
+
+	click(pixel.V(150, 50))
+	input.Scroll(pixel.V(0, 1))
+	frame()
+	want := []InputEvent{
+		{Button: pixel.MouseButtonLeft, Action: pixel.Press, Mouse: pixel.V(150, 50)},
+		{Button: pixel.MouseButtonLeft, Action: pixel.Release, Mouse: pixel.V(150, 50)},
+		{Scroll: pixel.V(0, 1), Mouse: pixel.V(150, 50)},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("unhandled events = %+v, want %+v", events, want)
+	}
+}
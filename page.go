@@ -0,0 +1,100 @@
+package pixelui
+
+import "github.com/gopxl/pixel/v2"
+
+// pagePicture is the picture of one atlas texture, made for the target it was last drawn to.
+type pagePicture struct {
+	picture pixel.TargetPicture
+	src     pixel.Picture
+	target  drawTarget
+}
+
+// pageRun is a stretch of the triangle buffer, up to end, sampling the same atlas texture.
+type pageRun struct {
+	page int
+	end  int
+}
+
+// addRun extends the last run up to end if it's on the same page, or starts a new one.
+//
+//	Only neighbouring commands are merged, so commands on different pages keep imgui's order.
+func (ui *UI) addRun(page, end int) {
+	if n := len(ui.runs); n > 0 && ui.runs[n-1].page == page {
+		ui.runs[n-1].end = end
+		return
+	}
+	ui.runs = append(ui.runs, pageRun{page: page, end: end})
+}
+
+// drawRuns draws each run of the triangle buffer with its atlas texture, one draw call per run.
+func (ui *UI) drawRuns(t drawTarget) {
+	start := 0
+	for _, run := range ui.runs {
+		if run.end > start {
+			tris := ui.shaderTris.Slice(start, run.end)
+			ui.atlasPicture(t, run.page).Draw(t.MakeTriangles(tris))
+			ui.drawCalls++
+		}
+		start = run.end
+	}
+}
+
+// DrawCalls returns the number of draw calls the last Draw issued for imgui's triangles.
+//
+//	Consecutive commands using the same atlas texture share a draw call; it only goes above one when
+//	the atlas spills onto more textures or draw callbacks split the triangles.
+func (ui *UI) DrawCalls() int {
+	return ui.drawCalls
+}
+
+// pageOf returns the index of the atlas texture the given id was packed into.
+func (ui *UI) pageOf(id uint32) int {
+	if page, ok := ui.pages[id]; ok {
+		return page
+	}
+	if ui.pages == nil {
+		ui.pages = make(map[uint32]int)
+	}
+
+	page := 0
+	if textures := ui.atlas.Textures(); len(textures) > 1 {
+		// The atlas has no way to ask which texture an id is on, but drawing it asks the target for a
+		//	picture of that texture.
+		var probe pageProbe
+		tex := ui.atlas.Get(id)
+		tex.Draw(&probe, pixel.IM)
+		for i, t := range textures {
+			if pixel.Picture(t) == probe.pic {
+				page = i
+			}
+		}
+	}
+	ui.pages[id] = page
+	return page
+}
+
+// pageProbe is a pixel.Target that draws nothing, only remembering the picture it was asked for.
+type pageProbe struct {
+	pic pixel.Picture
+}
+
+func (p *pageProbe) MakeTriangles(t pixel.Triangles) pixel.TargetTriangles {
+	return probeTriangles{t}
+}
+
+func (p *pageProbe) MakePicture(pic pixel.Picture) pixel.TargetPicture {
+	p.pic = pic
+	return probePicture{pic}
+}
+
+type probeTriangles struct {
+	pixel.Triangles
+}
+
+func (probeTriangles) Draw() {}
+
+type probePicture struct {
+	pixel.Picture
+}
+
+func (probePicture) Draw(pixel.TargetTriangles) {}
@@ -0,0 +1,163 @@
+package pixelui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gopxl/pixel/v2"
+)
+
+// inputFrame is the input of one frame, as written by RecordInput and read by ReplayInput.
+type inputFrame struct {
+	Mouse   pixel.Vec
+	Scroll  pixel.Vec
+	Typed   string        `json:",omitempty"`
+	Events  []buttonEvent `json:",omitempty"`
+	Outside bool          `json:",omitempty"`
+}
+
+// buttonEvent is a button callback received during a frame.
+type buttonEvent struct {
+	Button pixel.Button
+	Action pixel.Action
+}
+
+// inputRecorder wraps the UI's input source and writes what the UI reads from it every frame.
+type inputRecorder struct {
+	InputSource
+	enc    *json.Encoder
+	events []buttonEvent
+	err    error
+}
+
+func (r *inputRecorder) SetButtonCallback(callback func(button pixel.Button, action pixel.Action)) {
+	r.InputSource.SetButtonCallback(func(button pixel.Button, action pixel.Action) {
+		r.events = append(r.events, buttonEvent{button, action})
+		callback(button, action)
+	})
+}
+
+// Bounds passes on the bounds of the recorded source
+func (r *inputRecorder) Bounds() pixel.Rect {
+	return sourceBounds(r.InputSource)
+}
+
+// MouseInsideWindow passes on whether the mouse is inside the recorded source's window
+func (r *inputRecorder) MouseInsideWindow() bool {
+	return sourceMouseInside(r.InputSource)
+}
+
+// record writes the current frame, the first write error stops the recording.
+func (r *inputRecorder) record() {
+	if r.err != nil {
+		return
+	}
+	r.err = r.enc.Encode(inputFrame{
+		Mouse:   r.MousePosition(),
+		Scroll:  r.MouseScroll(),
+		Typed:   r.Typed(),
+		Events:  r.events,
+		Outside: !r.MouseInsideWindow(),
+	})
+	r.events = nil
+}
+
+// inputReplay feeds recorded frames to the UI through a TestInput, then hands back the previous source.
+type inputReplay struct {
+	*TestInput
+	frames  []inputFrame
+	prev    InputSource
+	outside bool
+}
+
+// Bounds passes on the bounds of the source the replay stands in for
+func (r *inputReplay) Bounds() pixel.Rect {
+	return sourceBounds(r.prev)
+}
+
+// MouseInsideWindow returns whether the mouse was inside the window when the frame was recorded
+func (r *inputReplay) MouseInsideWindow() bool {
+	return !r.outside
+}
+
+// RecordInput writes the mouse, scroll, typed text, button events and whether the mouse is inside the
+//
+//	window to w, one JSON object per frame, until StopRecordingInput is called. Start recording with
+//	no buttons held, as presses are only recorded as they happen.
+func (ui *UI) RecordInput(w io.Writer) {
+	ui.StopRecordingInput()
+	ui.recorder = &inputRecorder{InputSource: ui.input, enc: json.NewEncoder(w)}
+	ui.SetInputSource(ui.recorder)
+}
+
+// StopRecordingInput stops a recording started with RecordInput, returning the first error writing it
+func (ui *UI) StopRecordingInput() error {
+	r := ui.recorder
+	if r == nil {
+		return nil
+	}
+	ui.recorder = nil
+	if ui.input == r {
+		ui.SetInputSource(r.InputSource)
+	}
+	return r.err
+}
+
+// ReplayInput reads a recording made with RecordInput and feeds it to the UI in place of its input
+//
+//	source, one recorded frame per NewFrame. The previous source is restored once the recording ends.
+func (ui *UI) ReplayInput(r io.Reader) error {
+	var frames []inputFrame
+	dec := json.NewDecoder(r)
+	for {
+		var frame inputFrame
+		if err := dec.Decode(&frame); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("pixelui: failed to read the input recording: %w", err)
+		}
+		frames = append(frames, frame)
+	}
+
+	prev := ui.input
+	if ui.replay != nil {
+		prev = ui.replay.prev
+	}
+	ui.replay = &inputReplay{TestInput: NewTestInput(), frames: frames, prev: prev}
+	ui.SetInputSource(ui.replay)
+	return nil
+}
+
+// stepInput records or replays the input of the frame that is starting. Called from NewFrame.
+func (ui *UI) stepInput() {
+	if ui.recorder != nil && ui.input == ui.recorder {
+		ui.recorder.record()
+	}
+
+	r := ui.replay
+	if r == nil {
+		return
+	}
+	if len(r.frames) == 0 {
+		ui.replay = nil
+		ui.SetInputSource(r.prev)
+		return
+	}
+
+	frame := r.frames[0]
+	r.frames = r.frames[1:]
+
+	r.Update()
+	r.MoveMouse(frame.Mouse)
+	r.Scroll(frame.Scroll)
+	r.Type(frame.Typed)
+	r.outside = frame.Outside
+	for _, e := range frame.Events {
+		if e.Action == pixel.Release {
+			r.Release(e.Button)
+		} else {
+			r.Press(e.Button)
+		}
+	}
+}
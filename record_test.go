@@ -0,0 +1,100 @@
+package pixelui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ui, input := newTestUI(t)
+	var buf bytes.Buffer
+	ui.RecordInput(&buf)
+
+	input.MoveMouse(pixel.V(30, 40))
+	input.Press(pixel.MouseButtonLeft)
+	ui.NewFrame()
+	input.Update()
+	input.Release(pixel.MouseButtonLeft)
+	input.MoveMouse(pixel.V(50, 20))
+	input.Scroll(pixel.V(0, 2))
+	ui.NewFrame()
+	ui.DiscardFrame()
+	if err := ui.StopRecordingInput(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, _ := newTestUI(t)
+	if err := replayed.ReplayInput(&buf); err != nil {
+		t.Fatal(err)
+	}
+	frames := []struct {
+		mouse  imgui.Vec2
+		down   bool
+		scroll float32
+	}{
+		{IV(30, 60), true, 0},
+		{IV(50, 80), false, 2},
+	}
+	for i, want := range frames {
+		replayed.NewFrame()
+		if got := replayed.io.MousePosition(); got != want.mouse {
+			t.Errorf("frame %d: mouse position = %v, want %v", i, got, want.mouse)
+		}
+		if got := imgui.IsMouseDown(0); got != want.down {
+			t.Errorf("frame %d: left button down = %v, want %v", i, got, want.down)
+		}
+		if _, got := replayed.io.MouseWheel(); got != want.scroll {
+			t.Errorf("frame %d: mouse wheel = %v, want %v", i, got, want.scroll)
+		}
+	}
+	replayed.DiscardFrame()
+}
+
+// insideInput is a TestInput that can tell the mouse has left the window, like a Pixel window
+type insideInput struct {
+	*TestInput
+	inside bool
+}
+
+func (i *insideInput) MouseInsideWindow() bool {
+	return i.inside
+}
+
+func TestRecordReplayMouseInsideWindow(t *testing.T) {
+	ui, input := newTestUI(t)
+	src := &insideInput{TestInput: input, inside: true}
+	ui.SetInputSource(src)
+	var buf bytes.Buffer
+	ui.RecordInput(&buf)
+
+	var want []imgui.Vec2
+	for _, inside := range []bool{true, false, true} {
+		input.Update()
+		input.MoveMouse(pixel.V(30, 40))
+		src.inside = inside
+		ui.NewFrame()
+		want = append(want, ui.io.MousePosition())
+		ui.DiscardFrame()
+	}
+	if err := ui.StopRecordingInput(); err != nil {
+		t.Fatal(err)
+	}
+	if want[1] == want[0] {
+		t.Fatalf("mouse position outside the window = %v, want imgui's no mouse position", want[1])
+	}
+
+	replayed, _ := newTestUI(t)
+	if err := replayed.ReplayInput(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		replayed.NewFrame()
+		if got := replayed.io.MousePosition(); got != want[i] {
+			t.Errorf("frame %d: replayed mouse position = %v, want %v", i, got, want[i])
+		}
+		replayed.DiscardFrame()
+	}
+}
@@ -0,0 +1,170 @@
+package pixelui
+
+import (
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+)
+
+// InputSource is where the UI reads the mouse and keyboard state from each frame.
+type InputSource interface {
+	MousePosition() pixel.Vec
+	MouseScroll() pixel.Vec
+	Pressed(button pixel.Button) bool
+	JustPressed(button pixel.Button) bool
+	JustReleased(button pixel.Button) bool
+	Repeated(button pixel.Button) bool
+	Typed() string
+	SetButtonCallback(callback func(button pixel.Button, action pixel.Action))
+}
+
+// windowInput is the default InputSource, reading straight from the Pixel window.
+type windowInput struct {
+	*opengl.Window
+}
+
+func (w windowInput) SetButtonCallback(callback func(button pixel.Button, action pixel.Action)) {
+	w.Window.SetButtonCallback(func(win *opengl.Window, button pixel.Button, action pixel.Action) {
+		callback(button, action)
+	})
+}
+
+// TestInput is an InputSource driven by code instead of a window, for deterministic tests of widget interaction.
+//
+//	Like a window, the per-frame state (just pressed/released, scroll, typed text) is cleared by Update.
+type TestInput struct {
+	bounds   pixel.Rect
+	mouse    pixel.Vec
+	scroll   pixel.Vec
+	typed    string
+	pressed  map[pixel.Button]bool
+	just     map[pixel.Button]pixel.Action
+	callback func(button pixel.Button, action pixel.Action)
+}
+
+// NewTestInput creates an empty TestInput
+func NewTestInput() *TestInput {
+	return &TestInput{
+		pressed: make(map[pixel.Button]bool),
+		just:    make(map[pixel.Button]pixel.Action),
+	}
+}
+
+// Update clears the per-frame input state, call it once per frame like opengl.Window.Update
+func (t *TestInput) Update() {
+	t.scroll = pixel.ZV
+	t.typed = ""
+	t.just = make(map[pixel.Button]pixel.Action)
+}
+
+// SetBounds sets the area of the window the UI covers, in Pixel coordinates. Without bounds a UI reads
+//
+//	them from its window, or a headless one uses its display size.
+func (t *TestInput) SetBounds(r pixel.Rect) {
+	t.bounds = r
+}
+
+// Bounds returns the bounds set with SetBounds
+func (t *TestInput) Bounds() pixel.Rect {
+	return t.bounds
+}
+
+// MoveMouse moves the mouse to the given Pixel coordinate
+func (t *TestInput) MoveMouse(p pixel.Vec) {
+	t.mouse = p
+}
+
+// Scroll adds the given amount to this frame's mouse scroll
+func (t *TestInput) Scroll(v pixel.Vec) {
+	t.scroll = t.scroll.Add(v)
+}
+
+// Type adds the given text to this frame's typed characters
+func (t *TestInput) Type(text string) {
+	t.typed += text
+}
+
+// Press presses the given button
+func (t *TestInput) Press(button pixel.Button) {
+	if t.pressed[button] {
+		t.just[button] = pixel.Repeat
+		t.fire(button, pixel.Repeat)
+		return
+	}
+	t.pressed[button] = true
+	t.just[button] = pixel.Press
+	t.fire(button, pixel.Press)
+}
+
+// Release releases the given button
+func (t *TestInput) Release(button pixel.Button) {
+	if !t.pressed[button] {
+		return
+	}
+	delete(t.pressed, button)
+	t.just[button] = pixel.Release
+	t.fire(button, pixel.Release)
+}
+
+// fire forwards a button event to the registered callback
+func (t *TestInput) fire(button pixel.Button, action pixel.Action) {
+	if t.callback != nil {
+		t.callback(button, action)
+	}
+}
+
+func (t *TestInput) MousePosition() pixel.Vec {
+	return t.mouse
+}
+
+func (t *TestInput) MouseScroll() pixel.Vec {
+	return t.scroll
+}
+
+func (t *TestInput) Pressed(button pixel.Button) bool {
+	return t.pressed[button]
+}
+
+func (t *TestInput) JustPressed(button pixel.Button) bool {
+	action, ok := t.just[button]
+	return ok && action == pixel.Press
+}
+
+func (t *TestInput) JustReleased(button pixel.Button) bool {
+	action, ok := t.just[button]
+	return ok && action == pixel.Release
+}
+
+func (t *TestInput) Repeated(button pixel.Button) bool {
+	action, ok := t.just[button]
+	return ok && action == pixel.Repeat
+}
+
+func (t *TestInput) Typed() string {
+	return t.typed
+}
+
+func (t *TestInput) SetButtonCallback(callback func(button pixel.Button, action pixel.Action)) {
+	t.callback = callback
+}
+
+// sourceBounds returns the bounds of src, if it has any, see TestInput.SetBounds
+func sourceBounds(src InputSource) pixel.Rect {
+	if b, ok := src.(interface{ Bounds() pixel.Rect }); ok {
+		return b.Bounds()
+	}
+	return pixel.Rect{}
+}
+
+// sourceMouseInside returns false if src can tell the mouse has left the window, true if it can't tell
+func sourceMouseInside(src InputSource) bool {
+	if m, ok := src.(interface{ MouseInsideWindow() bool }); ok {
+		return m.MouseInsideWindow()
+	}
+	return true
+}
+
+// SetInputSource changes where the UI reads its input from; by default this is the window passed to New
+func (ui *UI) SetInputSource(src InputSource) {
+	ui.input = src
+	ui.input.SetButtonCallback(ui.buttonCallback)
+}
@@ -0,0 +1,46 @@
+package pixelui
+
+import (
+	"fmt"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// ExampleTestInput clicks a button without a window: a headless UI reads its mouse from a TestInput.
+func ExampleTestInput() {
+	input := NewTestInput()
+	input.SetBounds(pixel.R(0, 0, 320, 240))
+	ui, err := NewHeadless(input, nil, Options{Flags: OWN_ATLAS})
+	if err != nil {
+		panic(err)
+	}
+	ui.SetIniFilename("")
+
+	var button pixel.Vec
+	clicked := false
+	frame := func() {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.Begin("Example")
+		if imgui.Button("Click me") {
+			clicked = true
+		}
+		// The button's center, in Pixel coordinates.
+		button = ui.ToPixel(PV(imgui.ItemRectMin()).Add(PV(imgui.ItemRectMax())).Scaled(0.5))
+		imgui.End()
+		ui.DiscardFrame()
+		input.Update()
+	}
+
+	frame()
+	input.MoveMouse(button)
+	frame()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	input.Release(pixel.MouseButtonLeft)
+	frame()
+
+	fmt.Println("clicked:", clicked)
+	// Output: clicked: true
+}
@@ -0,0 +1,338 @@
+package pixelui
+
+import (
+	"image/color"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// SetUIScale scales the fonts and the style sizes (padding, spacing, rounding, ...) of the UI
+func (ui *UI) SetUIScale(scale float32) {
+	if scale <= 0 || scale == ui.scale {
+		return
+	}
+
+	ui.io.SetFontGlobalScale(scale)
+
+	// ScaleAllSizes multiplies the current sizes, so scale relative to what was applied last.
+	imgui.CurrentStyle().ScaleAllSizes(scale / ui.scale)
+	ui.scale = scale
+}
+
+// UIScale returns the scale set with SetUIScale
+func (ui *UI) UIScale() float32 {
+	return ui.scale
+}
+
+// SetAntiAliasing turns imgui's anti-aliasing of lines and borders, and of filled shapes, on or off.
+//
+//	Both are on by default; turning them off emits fewer vertices at the cost of jagged edges.
+func (ui *UI) SetAntiAliasing(lines, fill bool) {
+	style := imgui.CurrentStyle()
+	style.SetAntiAliasedLines(lines)
+	style.SetAntiAliasedFill(fill)
+}
+
+// PushStyleColor pushes a Go color for the given style color onto imgui's style stack
+func (ui *UI) PushStyleColor(idx imgui.StyleColorID, c color.Color) {
+	imgui.PushStyleColor(idx, IColor(c))
+}
+
+// PopStyleColor pops count colors pushed with PushStyleColor
+func (ui *UI) PopStyleColor(count int) {
+	imgui.PopStyleColorV(count)
+}
+
+// fontSize returns the size of the current font, including the UI scale.
+func (ui *UI) fontSize() float32 {
+	// imgui's text line height is exactly the current font size.
+	return imgui.TextLineHeight()
+}
+
+// SetNextItemWidthEm sets the width of the next item in multiples of the font size, so it scales with the UI
+func (ui *UI) SetNextItemWidthEm(em float32) {
+	imgui.SetNextItemWidth(em * ui.fontSize())
+}
+
+// SameLineEm places the next item on the same line, em font sizes after the previous one
+func (ui *UI) SameLineEm(em float32) {
+	imgui.SameLineV(0, em*ui.fontSize())
+}
+
+// StylePreset selects a set of style sizes for ApplyStylePreset.
+type StylePreset int
+
+// Style presets for ApplyStylePreset:
+//
+//	StyleCompact: small padding and spacing to fit more widgets.
+//	StyleFlat: square corners with imgui's default padding.
+//	StyleRounded: rounded windows and widgets with roomier padding.
+const (
+	StyleCompact StylePreset = iota
+	StyleFlat
+	StyleRounded
+)
+
+// stylePreset holds the unscaled sizes a StylePreset sets.
+type stylePreset struct {
+	windowRounding, frameRounding, grabRounding float32
+	windowPadding, framePadding, itemSpacing    imgui.Vec2
+}
+
+var stylePresets = map[StylePreset]stylePreset{
+	StyleCompact: {2, 1, 1, imgui.Vec2{X: 4, Y: 4}, imgui.Vec2{X: 3, Y: 1}, imgui.Vec2{X: 4, Y: 2}},
+	StyleFlat:    {0, 0, 0, imgui.Vec2{X: 8, Y: 8}, imgui.Vec2{X: 4, Y: 3}, imgui.Vec2{X: 8, Y: 4}},
+	StyleRounded: {8, 4, 4, imgui.Vec2{X: 10, Y: 10}, imgui.Vec2{X: 6, Y: 4}, imgui.Vec2{X: 8, Y: 6}},
+}
+
+// ApplyStylePreset sets the window, frame and grab rounding, padding and item spacing of the preset in
+//
+//	one call, scaled by the UI scale. Colors and other sizes are left alone.
+func (ui *UI) ApplyStylePreset(preset StylePreset) {
+	p, ok := stylePresets[preset]
+	if !ok {
+		return
+	}
+
+	scaled := func(v imgui.Vec2) imgui.Vec2 {
+		return imgui.Vec2{X: v.X * ui.scale, Y: v.Y * ui.scale}
+	}
+	style := imgui.CurrentStyle()
+	style.SetWindowRounding(p.windowRounding * ui.scale)
+	style.SetFrameRounding(p.frameRounding * ui.scale)
+	style.SetGrabRounding(p.grabRounding * ui.scale)
+	style.SetWindowPadding(scaled(p.windowPadding))
+	style.SetFramePadding(scaled(p.framePadding))
+	style.SetItemSpacing(scaled(p.itemSpacing))
+}
+
+// StyleData is a plain copy of imgui's style that can be saved, e.g. as a JSON theme file.
+//
+//	Colors are keyed by the name of the style color without the StyleColor prefix ("WindowBg", ...)
+//	and are non-premultiplied, like imgui's. Sizes are in pixels, including the UI scale at export.
+//	imgui-go only exposes some of the style's sizes, the others are left as they are.
+type StyleData struct {
+	Colors map[string]color.NRGBA
+
+	WindowPadding    pixel.Vec
+	FramePadding     pixel.Vec
+	ItemSpacing      pixel.Vec
+	ItemInnerSpacing pixel.Vec
+	WindowRounding   float32
+	FrameRounding    float32
+	GrabRounding     float32
+}
+
+// styleColors names imgui's style colors for StyleData.
+var styleColors = map[string]imgui.StyleColorID{
+	"Text":                  imgui.StyleColorText,
+	"TextDisabled":          imgui.StyleColorTextDisabled,
+	"WindowBg":              imgui.StyleColorWindowBg,
+	"ChildBg":               imgui.StyleColorChildBg,
+	"PopupBg":               imgui.StyleColorPopupBg,
+	"Border":                imgui.StyleColorBorder,
+	"BorderShadow":          imgui.StyleColorBorderShadow,
+	"FrameBg":               imgui.StyleColorFrameBg,
+	"FrameBgHovered":        imgui.StyleColorFrameBgHovered,
+	"FrameBgActive":         imgui.StyleColorFrameBgActive,
+	"TitleBg":               imgui.StyleColorTitleBg,
+	"TitleBgActive":         imgui.StyleColorTitleBgActive,
+	"TitleBgCollapsed":      imgui.StyleColorTitleBgCollapsed,
+	"MenuBarBg":             imgui.StyleColorMenuBarBg,
+	"ScrollbarBg":           imgui.StyleColorScrollbarBg,
+	"ScrollbarGrab":         imgui.StyleColorScrollbarGrab,
+	"ScrollbarGrabHovered":  imgui.StyleColorScrollbarGrabHovered,
+	"ScrollbarGrabActive":   imgui.StyleColorScrollbarGrabActive,
+	"CheckMark":             imgui.StyleColorCheckMark,
+	"SliderGrab":            imgui.StyleColorSliderGrab,
+	"SliderGrabActive":      imgui.StyleColorSliderGrabActive,
+	"Button":                imgui.StyleColorButton,
+	"ButtonHovered":         imgui.StyleColorButtonHovered,
+	"ButtonActive":          imgui.StyleColorButtonActive,
+	"Header":                imgui.StyleColorHeader,
+	"HeaderHovered":         imgui.StyleColorHeaderHovered,
+	"HeaderActive":          imgui.StyleColorHeaderActive,
+	"Separator":             imgui.StyleColorSeparator,
+	"SeparatorHovered":      imgui.StyleColorSeparatorHovered,
+	"SeparatorActive":       imgui.StyleColorSeparatorActive,
+	"ResizeGrip":            imgui.StyleColorResizeGrip,
+	"ResizeGripHovered":     imgui.StyleColorResizeGripHovered,
+	"ResizeGripActive":      imgui.StyleColorResizeGripActive,
+	"Tab":                   imgui.StyleColorTab,
+	"TabHovered":            imgui.StyleColorTabHovered,
+	"TabActive":             imgui.StyleColorTabActive,
+	"TabUnfocused":          imgui.StyleColorTabUnfocused,
+	"TabUnfocusedActive":    imgui.StyleColorTabUnfocusedActive,
+	"PlotLines":             imgui.StyleColorPlotLines,
+	"PlotLinesHovered":      imgui.StyleColorPlotLinesHovered,
+	"PlotHistogram":         imgui.StyleColorPlotHistogram,
+	"PlotHistogramHovered":  imgui.StyleColorPlotHistogramHovered,
+	"TableHeaderBg":         imgui.StyleColorTableHeaderBg,
+	"TableBorderStrong":     imgui.StyleColorTableBorderStrong,
+	"TableBorderLight":      imgui.StyleColorTableBorderLight,
+	"TableRowBg":            imgui.StyleColorTableRowBg,
+	"TableRowBgAlt":         imgui.StyleColorTableRowBgAlt,
+	"TextSelectedBg":        imgui.StyleColorTextSelectedBg,
+	"DragDropTarget":        imgui.StyleColorDragDropTarget,
+	"NavHighlight":          imgui.StyleColorNavHighlight,
+	"NavWindowingHighlight": imgui.StyleColorNavWindowingHighlight,
+	"NavWindowingDarkening": imgui.StyleColorNavWindowingDarkening,
+	"ModalWindowDarkening":  imgui.StyleColorModalWindowDarkening,
+}
+
+// ExportStyle copies the current style into a StyleData
+func (ui *UI) ExportStyle() StyleData {
+	style := imgui.CurrentStyle()
+	data := StyleData{
+		Colors: make(map[string]color.NRGBA, len(styleColors)),
+
+		WindowPadding:    PV(style.WindowPadding()),
+		FramePadding:     PV(style.FramePadding()),
+		ItemSpacing:      PV(style.ItemSpacing()),
+		ItemInnerSpacing: PV(style.ItemInnerSpacing()),
+		WindowRounding:   style.WindowRounding(),
+		FrameRounding:    style.FrameRounding(),
+		GrabRounding:     style.GrabRounding(),
+	}
+	for name, id := range styleColors {
+		c := style.Color(id)
+		data.Colors[name] = floatsToColor([4]float32{c.X, c.Y, c.Z, c.W})
+	}
+	return data
+}
+
+// ImportStyle sets the current style from a StyleData, e.g. one loaded from a theme file.
+//
+//	Colors missing from the data or with unknown names are left as they are.
+func (ui *UI) ImportStyle(data StyleData) {
+	style := imgui.CurrentStyle()
+	for name, c := range data.Colors {
+		if id, ok := styleColors[name]; ok {
+			style.SetColor(id, IColor(c))
+		}
+	}
+
+	style.SetWindowPadding(IVec(data.WindowPadding))
+	style.SetFramePadding(IVec(data.FramePadding))
+	style.SetItemSpacing(IVec(data.ItemSpacing))
+	style.SetItemInnerSpacing(IVec(data.ItemInnerSpacing))
+	style.SetWindowRounding(data.WindowRounding)
+	style.SetFrameRounding(data.FrameRounding)
+	style.SetGrabRounding(data.GrabRounding)
+}
+
+// Every style setting imgui-go can read and write, for carrying the style over to a new context.
+var (
+	styleFloats = []struct {
+		get func(imgui.Style) float32
+		set func(imgui.Style, float32)
+	}{
+		{imgui.Style.Alpha, imgui.Style.SetAlpha},
+		{imgui.Style.DisabledAlpha, imgui.Style.SetDisabledAlpha},
+		{imgui.Style.WindowRounding, imgui.Style.SetWindowRounding},
+		{imgui.Style.WindowBorderSize, imgui.Style.SetWindowBorderSize},
+		{imgui.Style.ChildRounding, imgui.Style.SetChildRounding},
+		{imgui.Style.ChildBorderSize, imgui.Style.SetChildBorderSize},
+		{imgui.Style.PopupRounding, imgui.Style.SetPopupRounding},
+		{imgui.Style.PopupBorderSize, imgui.Style.SetPopupBorderSize},
+		{imgui.Style.FrameRounding, imgui.Style.SetFrameRounding},
+		{imgui.Style.FrameBorderSize, imgui.Style.SetFrameBorderSize},
+		{imgui.Style.IndentSpacing, imgui.Style.SetIndentSpacing},
+		{imgui.Style.ColumnsMinSpacing, imgui.Style.SetColumnsMinSpacing},
+		{imgui.Style.ScrollbarSize, imgui.Style.SetScrollbarSize},
+		{imgui.Style.ScrollbarRounding, imgui.Style.SetScrollbarRounding},
+		{imgui.Style.GrabMinSize, imgui.Style.SetGrabMinSize},
+		{imgui.Style.GrabRounding, imgui.Style.SetGrabRounding},
+		{imgui.Style.LogSliderDeadzone, imgui.Style.SetLogSliderDeadzone},
+		{imgui.Style.TabRounding, imgui.Style.SetTabRounding},
+		{imgui.Style.TabBorderSize, imgui.Style.SetTabBorderSize},
+		{imgui.Style.TabMinWidthForCloseButton, imgui.Style.SetTabMinWidthForCloseButton},
+		{imgui.Style.MouseCursorScale, imgui.Style.SetMouseCursorScale},
+		{imgui.Style.CurveTessellationTol, imgui.Style.SetCurveTessellationTol},
+		{imgui.Style.CircleTessellationMaxError, imgui.Style.SetCircleTessellationMaxError},
+	}
+	styleVecs = []struct {
+		get func(imgui.Style) imgui.Vec2
+		set func(imgui.Style, imgui.Vec2)
+	}{
+		{imgui.Style.WindowPadding, imgui.Style.SetWindowPadding},
+		{imgui.Style.FramePadding, imgui.Style.SetFramePadding},
+		{imgui.Style.CellPadding, imgui.Style.SetCellPadding},
+		{imgui.Style.ItemSpacing, imgui.Style.SetItemSpacing},
+		{imgui.Style.ItemInnerSpacing, imgui.Style.SetItemInnerSpacing},
+		{imgui.Style.TouchExtraPadding, imgui.Style.SetTouchExtraPadding},
+		{imgui.Style.WindowMinSize, imgui.Style.SetWindowMinSize},
+		{imgui.Style.WindowTitleAlign, imgui.Style.SetWindowTitleAlign},
+		{imgui.Style.ButtonTextAlign, imgui.Style.SetButtonTextAlign},
+		{imgui.Style.SelectableTextAlign, imgui.Style.SetSelectableTextAlign},
+		{imgui.Style.DisplayWindowPadding, imgui.Style.SetDisplayWindowPadding},
+		{imgui.Style.DisplaySafeAreaPadding, imgui.Style.SetDisplaySafeAreaPadding},
+	}
+	styleBools = []struct {
+		get func(imgui.Style) bool
+		set func(imgui.Style, bool)
+	}{
+		{imgui.Style.AntiAliasedLines, imgui.Style.SetAntiAliasedLines},
+		{imgui.Style.AntiAliasedLinesUseTex, imgui.Style.SetAntiAliasedLinesUseTex},
+		{imgui.Style.AntiAliasedFill, imgui.Style.SetAntiAliasedFill},
+	}
+	styleDirs = []struct {
+		get func(imgui.Style) imgui.Dir
+		set func(imgui.Style, imgui.Dir)
+	}{
+		{imgui.Style.WindowMenuButtonPosition, imgui.Style.SetWindowMenuButtonPosition},
+		{imgui.Style.ColorButtonPosition, imgui.Style.SetColorButtonPosition},
+	}
+)
+
+// styleCopy is a copy of the current context's style, see copyStyle.
+type styleCopy struct {
+	floats []float32
+	vecs   []imgui.Vec2
+	bools  []bool
+	dirs   []imgui.Dir
+	colors map[imgui.StyleColorID]imgui.Vec4
+}
+
+// copyStyle copies every setting of the current style, unlike ExportStyle's selection for themes.
+func copyStyle() styleCopy {
+	style := imgui.CurrentStyle()
+	c := styleCopy{colors: make(map[imgui.StyleColorID]imgui.Vec4, len(styleColors))}
+	for _, f := range styleFloats {
+		c.floats = append(c.floats, f.get(style))
+	}
+	for _, v := range styleVecs {
+		c.vecs = append(c.vecs, v.get(style))
+	}
+	for _, b := range styleBools {
+		c.bools = append(c.bools, b.get(style))
+	}
+	for _, d := range styleDirs {
+		c.dirs = append(c.dirs, d.get(style))
+	}
+	for _, id := range styleColors {
+		c.colors[id] = style.Color(id)
+	}
+	return c
+}
+
+// restore sets the current style to the copy.
+func (c styleCopy) restore() {
+	style := imgui.CurrentStyle()
+	for i, f := range styleFloats {
+		f.set(style, c.floats[i])
+	}
+	for i, v := range styleVecs {
+		v.set(style, c.vecs[i])
+	}
+	for i, b := range styleBools {
+		b.set(style, c.bools[i])
+	}
+	for i, d := range styleDirs {
+		d.set(style, c.dirs[i])
+	}
+	for id, col := range c.colors {
+		style.SetColor(id, col)
+	}
+}
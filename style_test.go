@@ -0,0 +1,116 @@
+package pixelui
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+func TestStyleRoundTrip(t *testing.T) {
+	context := imgui.CreateContext(nil)
+	defer context.Destroy()
+	ui := &UI{}
+
+	want := ui.ExportStyle()
+	want.FrameRounding += 3
+	ui.ImportStyle(want)
+
+	if got := ui.ExportStyle(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExportStyle after ImportStyle = %+v, want %+v", got, want)
+	}
+}
+
+func TestSetAntiAliasing(t *testing.T) {
+	ui, _ := newTestUI(t)
+	for _, tt := range []struct{ lines, fill bool }{{false, true}, {true, false}, {false, false}} {
+		ui.SetAntiAliasing(tt.lines, tt.fill)
+		style := imgui.CurrentStyle()
+		if lines, fill := style.AntiAliasedLines(), style.AntiAliasedFill(); lines != tt.lines || fill != tt.fill {
+			t.Errorf("SetAntiAliasing(%v, %v) left lines %v and fill %v", tt.lines, tt.fill, lines, fill)
+		}
+	}
+}
+
+func TestApplyStylePreset(t *testing.T) {
+	ui, _ := newTestUI(t)
+	style := imgui.CurrentStyle()
+
+	ui.ApplyStylePreset(StyleRounded)
+	rounded := ui.ExportStyle()
+	ui.ApplyStylePreset(StyleCompact)
+	if compact := ui.ExportStyle(); reflect.DeepEqual(compact, rounded) {
+		t.Error("the compact and rounded presets set the same style")
+	}
+
+	ui.ApplyStylePreset(StyleFlat)
+	if w, f, g := style.WindowRounding(), style.FrameRounding(), style.GrabRounding(); w != 0 || f != 0 || g != 0 {
+		t.Errorf("flat preset left window rounding %v, frame rounding %v and grab rounding %v", w, f, g)
+	}
+}
+
+func TestSetUIScale(t *testing.T) {
+	ui, _ := newTestUI(t)
+	style := imgui.CurrentStyle()
+	padding := style.WindowPadding()
+
+	for _, scale := range []float32{2, 1} {
+		ui.SetUIScale(scale)
+		if got, want := style.WindowPadding(), (imgui.Vec2{X: padding.X * scale, Y: padding.Y * scale}); got != want {
+			t.Errorf("UI scale %v: window padding = %v, want %v", scale, got, want)
+		}
+		ui.NewFrame()
+		// imgui's text line height is the font size times the global font scale.
+		if got := imgui.TextLineHeight(); got != defaultFontSize*scale {
+			t.Errorf("UI scale %v: font size = %v, want %v", scale, got, defaultFontSize*scale)
+		}
+		ui.DiscardFrame()
+	}
+}
+
+func TestPushStyleColor(t *testing.T) {
+	ui, _ := newTestUI(t)
+	// Red and blue are equal, so the color reads the same with the pixelui_bgra tag.
+	tint := color.NRGBA{R: 255, G: 64, B: 255, A: 255}
+	renderWindow(ui, func() {
+		ui.PushStyleColor(imgui.StyleColorButton, tint)
+		imgui.Button("Tinted")
+		ui.PopStyleColor(1)
+	})
+
+	tinted := false
+	for _, batch := range ui.DrawDataSlices() {
+		for _, c := range batch.Colors {
+			tinted = tinted || c == tint
+		}
+	}
+	if !tinted {
+		t.Errorf("no vertex has the pushed button color %v", tint)
+	}
+}
+
+func TestEmLayout(t *testing.T) {
+	ui, _ := newTestUI(t)
+	for _, scale := range []float32{1, 2} {
+		ui.SetUIScale(scale)
+		em := defaultFontSize * scale
+		var width, gap float32
+		renderWindow(ui, func() {
+			text := ""
+			ui.SetNextItemWidthEm(10)
+			imgui.InputText("##Field", &text)
+			right := imgui.ItemRectMax().X
+			width = right - imgui.ItemRectMin().X
+			ui.SameLineEm(2)
+			imgui.Button("B")
+			gap = imgui.ItemRectMin().X - right
+		})
+		if width != 10*em {
+			t.Errorf("UI scale %v: item width = %v, want %v", scale, width, 10*em)
+		}
+		if gap != 2*em {
+			t.Errorf("UI scale %v: spacing = %v, want %v", scale, gap, 2*em)
+		}
+	}
+}
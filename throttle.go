@@ -0,0 +1,69 @@
+package pixelui
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"unsafe"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// SetIdleThrottle makes Draw reuse the previous frame's triangles instead of rebuilding them when
+//
+//	no input arrived and imgui produced exactly the same draw data. The UI is still drawn every frame.
+func (ui *UI) SetIdleThrottle(enabled bool) {
+	ui.idleThrottle = enabled
+	ui.drawHash = 0
+}
+
+// updateIdle records whether any input arrived this frame. Called from prepareIO.
+func (ui *UI) updateIdle(scroll pixel.Vec, typed string) {
+	mouse := ui.input.MousePosition()
+	ui.idle = !ui.keyEvent && typed == "" && scroll == pixel.ZV && mouse == ui.lastMouse &&
+		!ui.input.Pressed(pixel.MouseButtonLeft) &&
+		!ui.input.Pressed(pixel.MouseButtonRight) &&
+		!ui.input.Pressed(pixel.MouseButtonMiddle)
+	ui.lastMouse = mouse
+	ui.keyEvent = false
+}
+
+// reuseTriangles returns true if the idle throttle allows drawing last frame's triangles as they are.
+func (ui *UI) reuseTriangles(data imgui.DrawData) bool {
+	if !ui.idleThrottle {
+		return false
+	}
+
+	// Draw callbacks have to run inline, which needs the full rebuild.
+	sum := drawDataHash(data)
+	reuse := ui.idle && len(ui.callbacks) == 0 && sum == ui.drawHash && ui.drawHash != 0
+	ui.drawHash = sum
+	return reuse
+}
+
+// drawDataHash fingerprints the draw data, to tell whether anything changed since the last frame.
+func drawDataHash(data imgui.DrawData) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	put := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+
+	for _, list := range data.CommandLists() {
+		vertices, vertexBytes := list.VertexBuffer()
+		indices, indexBytes := list.IndexBuffer()
+		h.Write(unsafe.Slice((*byte)(vertices), vertexBytes))
+		h.Write(unsafe.Slice((*byte)(indices), indexBytes))
+
+		for _, cmd := range list.Commands() {
+			clip := cmd.ClipRect()
+			put(uint64(cmd.TextureID()))
+			put(uint64(cmd.ElementCount()))
+			put(uint64(math.Float32bits(clip.X))<<32 | uint64(math.Float32bits(clip.Y)))
+			put(uint64(math.Float32bits(clip.Z))<<32 | uint64(math.Float32bits(clip.W)))
+		}
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,70 @@
+package pixelui
+
+import (
+	"testing"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+func TestIdleThrottle(t *testing.T) {
+	ui, input := newTestUI(t)
+	ui.SetIdleThrottle(true)
+
+	// frame renders a window with the given label, returning whether Draw would reuse the triangles.
+	frame := func(label string, callback bool) bool {
+		ui.NewFrame()
+		imgui.Begin("Test")
+		imgui.Text(label)
+		if callback {
+			ui.AddDrawCallback(func(*opengl.Window) {})
+		}
+		imgui.End()
+		imgui.Render()
+		ui.inFrame = false
+		input.Update()
+		return ui.reuseTriangles(imgui.RenderedDrawData())
+	}
+
+	// settle renders idle frames until imgui has shown and focused the window, after which the
+	//	triangles are reused.
+	settle := func(label string) {
+		t.Helper()
+		for i := 0; i < 3; i++ {
+			frame(label, false)
+		}
+		if !frame(label, false) {
+			t.Fatal("an idle frame didn't reuse the triangles")
+		}
+	}
+
+	settle("Hello")
+	if frame("World", false) {
+		t.Error("a changed frame reused the triangles")
+	}
+	settle("World")
+
+	input.MoveMouse(pixel.V(150, 10))
+	if frame("World", false) {
+		t.Error("a frame with input reused the triangles")
+	}
+	settle("World")
+
+	ui.SetClipRounding(4)
+	if frame("World", false) {
+		t.Error("the triangles were reused after SetClipRounding")
+	}
+	settle("World")
+
+	ui.invalidatePicture()
+	if frame("World", false) {
+		t.Error("the triangles were reused after the atlas changed")
+	}
+
+	settle("World")
+	frame("World", true)
+	if frame("World", true) {
+		t.Error("the triangles were reused with a draw callback")
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"github.com/inkyblackness/imgui-go/v4"
 )
 import (
+	"fmt"
+	"image"
 	"image/color"
+	"math"
 	"runtime"
 	"time"
-	"unsafe"
 
+	"github.com/gopxl/glhf/v2"
 	"github.com/gopxl/mainthread/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
 	"github.com/gopxl/pixel/v2/ext/atlas"
@@ -22,6 +25,7 @@ const uiShader = `
 in vec4  vColor;
 in vec2  vTexCoords;
 in float vIntensity;
+in vec2  vPosition;
 in vec4  vClipRect;
 
 out vec4 fragColor;
@@ -30,78 +34,453 @@ uniform vec4 uColorMask;
 uniform vec4 uTexBounds;
 uniform sampler2D uTexture;
 uniform vec4 uClipRect;
+uniform float uSRGB;
+
+// toLinear converts an sRGB color to linear, for framebuffers that blend in linear space.
+vec3 toLinear(vec3 c) {
+	return mix(c / 12.92, pow((c + 0.055) / 1.055, vec3(2.4)), step(0.04045, c));
+}
 
 void main() {
-	if ((vClipRect != vec4(0,0,0,0)) && (gl_FragCoord.x < vClipRect.x || gl_FragCoord.y < vClipRect.y || gl_FragCoord.x > vClipRect.z || gl_FragCoord.y > vClipRect.w))
+	// The clip rect and vPosition are both in imgui's coordinates, so unlike gl_FragCoord this doesn't
+	//	depend on the framebuffer's origin, size or the window's content scale.
+	if ((vClipRect != vec4(0,0,0,0)) && (vPosition.x < vClipRect.x || vPosition.y < vClipRect.y || vPosition.x > vClipRect.z || vPosition.y > vClipRect.w))
 		discard;
-	fragColor = vColor;
-	if (vIntensity == 0) {
-		fragColor *= vColor * texture(uTexture, vTexCoords).a;
+	// vIntensity carries the clip rect's corner radius on top of the texture kind, see clipIntensity.
+	float radius = floor(vIntensity / 2.0 + 0.25);
+	float kind = vIntensity - 2.0 * radius;
+	if (radius > 0) {
+		vec2 inner = clamp(vPosition, vClipRect.xy + radius, vClipRect.zw - radius);
+		if (distance(vPosition, inner) > radius)
+			discard;
+	}
+	// imgui's colors are straight alpha, Pixel's compose methods expect premultiplied ones.
+	vec3 rgb = vColor.rgb;
+	if (uSRGB != 0) {
+		rgb = toLinear(rgb);
+	}
+	vec4 color = vec4(rgb * vColor.a, vColor.a);
+	if (kind < 0.5) {
+		fragColor = color * texture(uTexture, vTexCoords).a;
 		fragColor *= uColorMask;
 	} else {
-		fragColor *= vColor * texture(uTexture, vTexCoords);
+		vec4 tex = texture(uTexture, vTexCoords);
+		if (uSRGB != 0 && tex.a > 0) {
+			tex.rgb = toLinear(tex.rgb / tex.a) * tex.a;
+		}
+		fragColor = color * tex;
 		fragColor *= uColorMask;
 	}
 }
 `
 
+// uiVertexShader passes the vertex attributes on like Pixel's canvas vertex shader, it is only used
+//
+//	to check that uiShader compiles and links, see compileShader.
+const uiVertexShader = `
+#version 330 core
+in vec2  aPosition;
+in vec4  aColor;
+in vec2  aTexCoords;
+in float aIntensity;
+in vec4  aClipRect;
+
+out vec4  vColor;
+out vec2  vTexCoords;
+out float vIntensity;
+out vec2  vPosition;
+out vec4  vClipRect;
+
+void main() {
+	gl_Position = vec4(aPosition, 0.0, 1.0);
+	vColor = aColor;
+	vTexCoords = aTexCoords;
+	vIntensity = aIntensity;
+	vPosition = aPosition;
+	vClipRect = aClipRect;
+}
+`
+
+// compileShader compiles and links the fragment shader on the main thread, returning the error
+//
+//	Pixel's GLShader would panic with.
+func compileShader(fragmentShader string) error {
+	return mainthread.CallErr(func() error {
+		_, err := glhf.NewShader(glhf.AttrFormat{}, glhf.AttrFormat{}, uiVertexShader, fragmentShader)
+		return err
+	})
+}
+
 // UI Stores the state of the pixelui UI
 type UI struct {
-	win        *opengl.Window
-	context    *imgui.Context
-	io         imgui.IO
-	fonts      imgui.FontAtlas
-	timer      time.Time
-	shader     *opengl.GLShader
-	matrix     pixel.Matrix
-	shaderTris *opengl.GLTriangles
-	atlas      *atlas.Atlas
-	group      atlas.Group
-	font       atlas.TextureId
-	cursors    map[imgui.MouseCursorID]*opengl.Cursor
+	win         *opengl.Window
+	options     Options
+	input       InputSource
+	clipboard   imgui.Clipboard
+	context     *imgui.Context
+	io          imgui.IO
+	fonts       imgui.FontAtlas
+	timer       time.Time
+	now         func() time.Time
+	maxDelta    time.Duration
+	delta       float32
+	frames      int
+	inFrame     bool
+	unwinding   bool
+	scale       float32
+	compose     pixel.ComposeMethod
+	smooth      bool
+	srgb        float32
+	display     pixel.Vec
+	menuBar     [2]float32
+	callbacks   []func(win *opengl.Window)
+	onError     func(msg string)
+	shader      *opengl.GLShader
+	matrix      pixel.Matrix
+	inverse     pixel.Matrix
+	inputMatrix pixel.Matrix
+	scrollSpeed pixel.Vec
+	shaderTris  *opengl.GLTriangles
+	batches     []DrawBatch
+	scales      []pixel.Vec
+	atlas       *atlas.Atlas
+	group       atlas.Group
+	fontGroup   atlas.Group
+	font        atlas.TextureId
+	fontFiles   []fontFile
+	fontNames   map[string]imgui.Font
+	alphaTex    map[uint32]bool
+	packed      []image.Image
+	pictures    []pagePicture
+	pages       map[uint32]int
+	runs        []pageRun
+	drawCalls   int
+	cursors     map[imgui.MouseCursorID]*opengl.Cursor
+	softCursor  bool
+	focus       string
+	hover       hoverTimer
+	activeField activeField
+
+	idleThrottle bool
+	idle         bool
+	keyEvent     bool
+	lastMouse    pixel.Vec
+	drawHash     uint64
+
+	captureMouse    bool
+	captureKeyboard bool
+	captureByHover  bool
+	mouseOverWindow bool
+
+	events    eventQueue
+	drained   []buttonEvent
+	unhandled func(ev InputEvent)
+
+	scaleSource  func() float32
+	contentScale float32
+	pendingScale float32
+	scaleFrames  int
+
+	cursorFallback  bool
+	softFallback    bool
+	fallbackVisible bool
+
+	clipRounding float64
+	pixelPerfect bool
+	builderFlags uint
+
+	clampWindows bool
+	clampPos     map[string]imgui.Vec2
+	popupPos     map[string]popupAt
+	savedLayout  string
+	iniFilename  string
+
+	recorder *inputRecorder
+	replay   *inputReplay
+
+	restoreMatrix  pixel.Matrix
+	restoreCompose pixel.ComposeMethod
 }
 
 var CurrentUI *UI
 
 // pixelui.NewUI flags:
 //
-//	NO_DEFAULT_FONT: Do not load the default font during New.
+//	NO_DEFAULT_FONT: Do not load the default font during New. If no font was added by the first frame,
+//		imgui's default font is baked then, as imgui can't start a frame without one.
+//	OWN_ATLAS: Create and pack into a private atlas instead of the one passed to New, so rebuilding
+//		fonts or adding images never repacks the caller's sprites. The atlas argument may be nil.
+//	NAV_KEYBOARD: Enable imgui's keyboard navigation (tab/arrows/space/enter between widgets).
+//	DETERMINISTIC_ATLAS: Like OWN_ATLAS, but every pack rebuilds the atlas from scratch with the images
+//		in the order they were added, so fonts and images land on the same frames on every run (e.g. for
+//		golden image tests). The UI keeps a copy of every image for this, and Atlas returns a new atlas
+//		after each pack. Sprites added to Group directly are dropped by the next pack.
 const (
 	NO_DEFAULT_FONT uint8 = 1 << iota
+	OWN_ATLAS
+	NAV_KEYBOARD
+	DETERMINISTIC_ATLAS
 )
 
+// defaultIniFilename is the file imgui keeps its window settings in unless changed with SetIniFilename.
+const defaultIniFilename = "imgui.ini"
+
+// defaultMaxDelta is the largest frame delta handed to imgui unless changed with SetMaxDeltaTime.
+const defaultMaxDelta = 100 * time.Millisecond
+
 // New Creates the UI and setups up its internal structures
+//
+//	It panics if the UI could not be created, see NewWithError.
 func New(win *opengl.Window, atlas *atlas.Atlas, flags uint8) *UI {
-	var context *imgui.Context
-	mainthread.Call(func() {
-		context = imgui.CreateContext(nil)
-	})
+	ui, err := NewWithError(win, atlas, flags)
+	if err != nil {
+		panic(err)
+	}
+	return ui
+}
+
+// NewWithError Creates the UI like New, but returns an error when the shader fails to compile
+//
+//	or the default font fails to bake instead of panicking.
+func NewWithError(win *opengl.Window, atlas *atlas.Atlas, flags uint8) (*UI, error) {
+	return NewWithOptions(win, atlas, Options{Flags: flags})
+}
+
+// Options configures NewWithOptions.
+//
+//	Flags: the New flags.
+//	DefaultFontSize: pixel size the default font is baked at, 0 uses imgui's 13 pixels.
+//	ComposeMethod: compose method used while drawing the UI, see SetComposeMethod. The zero value is
+//		pixel.ComposeOver.
+type Options struct {
+	Flags           uint8
+	DefaultFontSize float32
+	ComposeMethod   pixel.ComposeMethod
+}
+
+// NewWithOptions Creates the UI like NewWithError, configured by the given options
+func NewWithOptions(win *opengl.Window, atlas *atlas.Atlas, opts Options) (*UI, error) {
+	// Pixel panics on the main thread when the shader doesn't compile, which can't be recovered from
+	//	here, so make sure it compiles first.
+	if err := compileShader(uiShader); err != nil {
+		return nil, fmt.Errorf("pixelui: failed to create UI: %w", err)
+	}
+
+	ui := newUI(win, windowInput{win}, atlas, opts)
+
+	ui.shader = opengl.NewGLShader(uiShader)
+	ui.shader.SetUniform("uSRGB", &ui.srgb)
+	ui.shader.Update()
+
+	ui.shaderTris = opengl.NewGLTriangles(ui.shader, pixel.MakeTrianglesData(0))
+
+	if err := ui.finishNew(); err != nil {
+		return nil, err
+	}
+	return ui, nil
+}
+
+// NewHeadless Creates a UI without a window that reads its input from src, e.g. a TestInput, so widget
+//
+//	interaction can be tested without a display or GL. The UI covers src's Bounds if it has any (see
+//	TestInput.SetBounds), otherwise the size set with SetDisplaySize. A headless UI can't be drawn, end
+//	its frames with DiscardFrame or by starting the next one.
+func NewHeadless(src InputSource, atlas *atlas.Atlas, opts Options) (*UI, error) {
+	ui := newUI(nil, src, atlas, opts)
+	if err := ui.finishNew(); err != nil {
+		return nil, err
+	}
+	return ui, nil
+}
+
+// newUI creates the UI's imgui context and state, everything but the GL resources.
+func newUI(win *opengl.Window, src InputSource, atlas *atlas.Atlas, opts Options) *UI {
+	if opts.Flags&(OWN_ATLAS|DETERMINISTIC_ATLAS) != 0 {
+		atlas = newAtlas()
+	}
 
 	ui := &UI{
-		win:     win,
-		context: context,
-		atlas:   atlas,
-		group:   atlas.MakeGroup(),
-		cursors: make(map[imgui.MouseCursorID]*opengl.Cursor),
+		win:         win,
+		options:     opts,
+		input:       src,
+		atlas:       atlas,
+		group:       atlas.MakeGroup(),
+		fontGroup:   atlas.MakeGroup(),
+		alphaTex:    make(map[uint32]bool),
+		fontNames:   make(map[string]imgui.Font),
+		cursors:     make(map[imgui.MouseCursorID]*opengl.Cursor),
+		maxDelta:    defaultMaxDelta,
+		now:         time.Now,
+		scale:       1,
+		compose:     opts.ComposeMethod,
+		inputMatrix: pixel.IM,
+		scrollSpeed: pixel.V(1, 1),
+		iniFilename: defaultIniFilename,
+
+		captureMouse:    true,
+		captureKeyboard: true,
+
+		contentScale: 1,
+
+		restoreMatrix:  pixel.IM,
+		restoreCompose: pixel.ComposeOver,
 	}
-	CurrentUI = ui
+	if win != nil {
+		ui.smooth = win.Smooth()
+	} else {
+		// There is no window clipboard to fall back to.
+		ui.clipboard = funcClipboard{}
+	}
+
+	ui.call(func() {
+		ui.context = imgui.CreateContext(nil)
+	})
+	// imgui only makes a new context current if there was none, a second UI must switch to its own.
+	ui.context.SetCurrent()
+
+	ui.updateMatrix()
 
 	ui.io = imgui.CurrentIO()
 	ui.initIO()
+	if opts.Flags&NAV_KEYBOARD != 0 {
+		ui.io.SetConfigFlags(imgui.ConfigFlagsNavEnableKeyboard)
+	}
 
 	ui.fonts = ui.io.Fonts()
+	return ui
+}
 
-	ui.shader = opengl.NewGLShader(uiShader)
-
-	ui.shaderTris = opengl.NewGLTriangles(ui.shader, pixel.MakeTrianglesData(0))
+// finishNew packs the white pixel and the default font and makes the UI current, the last step of New.
+func (ui *UI) finishNew() error {
+	// Make sure the atlas has a texture even if no font or image is ever added.
+	ui.addWhitePixel()
 
-	if flags&NO_DEFAULT_FONT == 0 {
-		ui.loadDefaultFont()
+	if ui.options.Flags&NO_DEFAULT_FONT == 0 {
+		if err := ui.loadDefaultFont(ui.options.DefaultFontSize); err != nil {
+			ui.context.Destroy()
+			return err
+		}
 	}
 
+	CurrentUI = ui
 	runtime.SetFinalizer(ui, (*UI).destroy)
+	return nil
+}
 
-	return ui
+// call runs f on the main thread. A headless UI runs it directly, mainthread may not be running.
+func (ui *UI) call(f func()) {
+	if ui.win == nil {
+		f()
+		return
+	}
+	mainthread.Call(f)
+}
+
+// addWhitePixel packs a single white pixel into the UI's group, so the atlas always has a texture.
+func (ui *UI) addWhitePixel() {
+	white := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	white.SetRGBA(0, 0, color.RGBA{255, 255, 255, 255})
+	ui.addToGroup(white)
+	ui.pack()
+}
+
+// Reset destroys the imgui context and creates a fresh one, e.g. to recover after an imgui assertion.
+//
+//	The default font, fonts loaded from files and images are loaded again, so their ids stay valid,
+//	but imgui.Font handles returned before need to be looked up again. Window positions and other
+//	state kept by imgui are lost; save them with SaveLayout first to keep them. Settings made through
+//	the UI (scale, ini filename, cursor, keyboard navigation, ...) carry over to the new context.
+func (ui *UI) Reset() error {
+	old := ui.context
+	ui.call(func() {
+		ui.context = imgui.CreateContext(nil)
+	})
+	old.Destroy()
+	ui.makeCurrent()
+	ui.inFrame = false
+
+	ui.io = imgui.CurrentIO()
+	ui.fonts = ui.io.Fonts()
+	ui.fonts.SetFontBuilderFlags(ui.builderFlags)
+	ui.initIO()
+	if ui.options.Flags&NAV_KEYBOARD != 0 {
+		ui.io.SetConfigFlags(imgui.ConfigFlagsNavEnableKeyboard)
+	}
+	if ui.scale != 1 {
+		ui.io.SetFontGlobalScale(ui.scale)
+		imgui.CurrentStyle().ScaleAllSizes(ui.scale)
+	}
+	ui.focus = ""
+	ui.hover = hoverTimer{}
+	// Positions queued for windows and popups of the old context would be applied to the new one's.
+	clear(ui.clampPos)
+	clear(ui.popupPos)
+
+	return ui.reloadFonts()
+}
+
+// newAtlas creates an empty atlas for the OWN_ATLAS flag.
+func newAtlas() *atlas.Atlas {
+	return &atlas.Atlas{}
+}
+
+// addToGroup adds the image to the UI's group without packing the atlas.
+func (ui *UI) addToGroup(img image.Image) atlas.TextureId {
+	if ui.options.Flags&DETERMINISTIC_ATLAS != 0 {
+		// Ids are handed out in order from 0 in the UI's own atlas, so they index the copies.
+		ui.packed = append(ui.packed, img)
+	}
+	return ui.group.AddImage(img)
+}
+
+// Pack packs the sprites added to Group since the last pack, alongside the UI's fonts and images, and
+//
+//	makes the next Draw use the new atlas textures.
+func (ui *UI) Pack() {
+	ui.pack()
+}
+
+// pack packs the images added to the atlas and drops the cached pictures of the old textures.
+//
+//	With DETERMINISTIC_ATLAS it packs a fresh atlas instead: repacking an already packed atlas walks
+//	its frames in map order, which differs from run to run. Adding the same images in the same order
+//	to an empty atlas hands out the same ids and frames every time.
+func (ui *UI) pack() {
+	defer ui.invalidatePicture()
+	if ui.options.Flags&DETERMINISTIC_ATLAS == 0 {
+		ui.atlas.Pack()
+		return
+	}
+
+	a := newAtlas()
+	group := a.MakeGroup()
+	for _, img := range ui.packed {
+		group.AddImage(img)
+	}
+	a.Pack()
+	ui.atlas, ui.group, ui.fontGroup = a, group, a.MakeGroup()
+}
+
+// Atlas returns the atlas the UI packs its fonts and images into. Add sprites through Group and pack
+//
+//	them with Pack, so Draw picks up the new textures.
+func (ui *UI) Atlas() *atlas.Atlas {
+	return ui.atlas
+}
+
+// makeCurrent switches imgui to this UI's context so several UIs can be used side by side.
+func (ui *UI) makeCurrent() {
+	ui.context.SetCurrent()
+	CurrentUI = ui
+}
+
+// Group returns the atlas group the UI packs its images into.
+//
+//	Sprites added to it are packed alongside the UI's images, sharing the UI's texture page, and can be
+//	drawn by imgui as imgui.TextureID(tex.ID()). Call Pack after adding to it; packing the atlas
+//	directly leaves Draw sampling the old texture.
+func (ui *UI) Group() atlas.Group {
+	return ui.group
 }
 
 // Destroy cleans up the imgui context
@@ -111,32 +490,237 @@ func (ui *UI) destroy() {
 
 // NewFrame Call this at the beginning of the frame to tell the UI that the frame has started
 func (ui *UI) NewFrame() {
-	if !ui.timer.IsZero() && time.Since(ui.timer).Seconds() > 0.0 {
-		ui.io.SetDeltaTime(float32(time.Since(ui.timer).Seconds()))
-	} else {
-		ui.io.SetDeltaTime(0.001)
-	}
-	ui.timer = time.Now()
+	// imgui asserts if a frame is started while the last one is still open.
+	ui.DiscardFrame()
+
+	defer ui.recoverError()
+	ui.makeCurrent()
+
+	now := ui.now()
+	ui.delta = ui.frameDelta(now)
+	ui.io.SetDeltaTime(ui.delta)
+	ui.timer = now
+	ui.frames++
+	ui.callbacks = ui.callbacks[:0]
+	// This frame's and the last frame's main menu bar height, see WorkArea.
+	ui.menuBar = [2]float32{0, ui.menuBar[0]}
+
+	ui.updateMatrix()
+	ui.stepInput()
 
 	// imgui requires that io be set before calling NewFrame
 	ui.prepareIO()
+	ui.bakeFallbackFont()
 
 	imgui.NewFrame()
+	ui.inFrame = true
+	ui.mouseOverWindow = imgui.IsWindowHoveredV(imgui.HoveredFlagsAnyWindow)
+}
+
+// SafeFrame runs a whole frame: NewFrame, build and Draw. If build panics, the frame is ended without
+//
+//	being drawn and the panic is returned as an error, so the app can keep running. Windows left open
+//	by the panic are ended with it.
+func (ui *UI) SafeFrame(win *opengl.Window, build func()) error {
+	ui.NewFrame()
+	if err := buildFrame(build); err != nil {
+		ui.discardUnwound()
+		return err
+	}
+	ui.Draw(win)
+	return nil
+}
+
+// buildFrame runs build, returning a panic as an error.
+func buildFrame(build func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("pixelui: building the frame panicked: %v", r)
+		}
+	}()
+	build()
+	return nil
+}
+
+// discardUnwound ends a frame a panic left windows open in. imgui's EndFrame ends them itself, after
+//
+//	asserting that Begin and End don't match, so assertions are ignored while it does.
+func (ui *UI) discardUnwound() {
+	imgui.SetAssertHandler(assert)
+	ui.unwinding = true
+	defer func() { ui.unwinding = false }()
+	ui.DiscardFrame()
+}
+
+// DiscardFrame ends the frame started with NewFrame without drawing it, e.g. while the game is paused.
+//
+//	NewFrame discards the previous frame itself if it wasn't drawn.
+func (ui *UI) DiscardFrame() {
+	if !ui.inFrame {
+		return
+	}
+	ui.inFrame = false
+
+	defer ui.recoverError()
+	ui.makeCurrent()
+	imgui.EndFrame()
+}
+
+// frameDelta returns the seconds elapsed since the last frame, clamped to (0, ui.maxDelta].
+//
+//	A long pause (minimized window, breakpoint) would otherwise hand imgui a huge step.
+func (ui *UI) frameDelta(now time.Time) float32 {
+	delta := now.Sub(ui.timer)
+	if ui.timer.IsZero() || delta <= 0 {
+		return 0.001
+	}
+	if ui.maxDelta > 0 && delta > ui.maxDelta {
+		delta = ui.maxDelta
+	}
+	return float32(delta.Seconds())
+}
+
+// SetTimeSource sets the clock NewFrame measures the frame delta with, e.g. a fixed-step clock for
+//
+//	deterministic tests or replays. Pass nil to go back to time.Now, the default.
+func (ui *UI) SetTimeSource(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	ui.now = now
+	// The last frame's time came from the old clock, start measuring afresh.
+	ui.timer = time.Time{}
+}
+
+// SetMaxDeltaTime sets the largest frame delta passed to imgui. A value <= 0 disables clamping.
+func (ui *UI) SetMaxDeltaTime(d time.Duration) {
+	ui.maxDelta = d
+}
+
+// SetErrorHandler installs a handler for imgui assertions (mismatched Begin/End, ID stack issues, ...).
+//
+//	imgui-go turns assertions into panics; with a handler set, imgui's assertion hook passes them to
+//	the handler instead, wherever they are raised, and imgui carries on past the failed check. Panics
+//	in NewFrame, Draw and DiscardFrame are still recovered and passed to the handler as a fallback.
+//	Pass nil to let them panic again. The hook is shared, it calls the handler of the current UI.
+func (ui *UI) SetErrorHandler(handler func(msg string)) {
+	ui.onError = handler
+	imgui.SetAssertHandler(assert)
+}
+
+// assert is the imgui assertion hook, handing the assertion to the current UI's error handler or
+//
+//	panicking like imgui-go's default hook if there is none.
+func assert(expression, file string, line int) {
+	ui := CurrentUI
+	if ui != nil && ui.unwinding {
+		return
+	}
+	err := imgui.AssertionError{Expression: expression, File: file, Line: line}
+	if ui != nil && ui.onError != nil {
+		ui.onError(err.Error())
+		return
+	}
+	panic(err)
+}
+
+// recoverError hands a panic to the error handler, if one is installed. It must be deferred.
+func (ui *UI) recoverError() {
+	if ui.onError == nil {
+		return
+	}
+	if r := recover(); r != nil {
+		ui.onError(fmt.Sprint(r))
+	}
+}
+
+// DeltaTime returns the delta time in seconds passed to imgui by the last NewFrame
+func (ui *UI) DeltaTime() float32 {
+	return ui.delta
+}
+
+// FrameCount returns the number of frames started with NewFrame
+func (ui *UI) FrameCount() int {
+	return ui.frames
+}
+
+// Framerate returns imgui's estimate of the frames per second, averaged over the last 120 frames
+func (ui *UI) Framerate() float32 {
+	return ui.io.Framerate()
+}
+
+// MetricsRenderVertices returns the number of vertices in the draw data of the last Render
+func (ui *UI) MetricsRenderVertices() int {
+	return ui.io.MetricsRenderVertices()
+}
+
+// MetricsRenderIndices returns the number of indices in the draw data of the last Render
+func (ui *UI) MetricsRenderIndices() int {
+	return ui.io.MetricsRenderIndices()
+}
+
+// MetricsRenderWindows returns the number of windows drawn by the last Render
+func (ui *UI) MetricsRenderWindows() int {
+	return ui.io.MetricsRenderWindows()
+}
+
+// MetricsActiveWindows returns the number of windows active in the last frame
+func (ui *UI) MetricsActiveWindows() int {
+	return ui.io.MetricsActiveWindows()
 }
 
 // update Handles general update type things and handle inputs. Called from ui.Draw.
 func (ui *UI) update() {
 }
 
+// updateMatrix computes the imgui -> Pixel matrix and its inverse, once per frame.
 func (ui *UI) updateMatrix() {
-	ui.matrix = pixel.IM.ScaledXY(ui.win.Bounds().Center(), pixel.V(1, -1))
+	bounds := ui.bounds()
+	if ui.display == pixel.ZV {
+		ui.matrix = pixel.IM.ScaledXY(bounds.Center(), pixel.V(1, -1))
+	} else {
+		// Stretch the fixed display size over the whole window.
+		scale := bounds.Size().ScaledXY(ui.display.Map(recip))
+		ui.matrix = pixel.IM.ScaledXY(pixel.ZV, pixel.V(scale.X, -scale.Y)).Moved(pixel.V(bounds.Min.X, bounds.Max.Y))
+	}
+	ui.inverse = invert(ui.matrix)
+}
+
+// bounds returns the area of the window the UI covers, in Pixel coordinates: the input source's bounds
+//
+//	if it has any, otherwise the window's. A headless UI without either covers its display size.
+func (ui *UI) bounds() pixel.Rect {
+	if b := sourceBounds(ui.input); b.Area() > 0 {
+		return b
+	}
+	if ui.win == nil {
+		return pixel.R(0, 0, ui.display.X, ui.display.Y)
+	}
+	return ui.win.Bounds()
+}
+
+// displaySize returns the size imgui lays the UI out in.
+func (ui *UI) displaySize() pixel.Vec {
+	if ui.display == pixel.ZV {
+		return ui.bounds().Size()
+	}
+	return ui.display
+}
+
+// SetDisplaySize lays the UI out at a fixed size, e.g. a virtual resolution for pixel-art games, and
+//
+//	stretches it over the window when drawing. Mouse input is mapped into the same space. Pass
+//	pixel.ZV to follow the window size again, which is the default.
+func (ui *UI) SetDisplaySize(size pixel.Vec) {
+	ui.display = size
+	ui.io.SetDisplaySize(IVec(ui.displaySize()))
+	ui.updateMatrix()
 }
 
 // Draw Draws the imgui UI to the Pixel Window
 func (ui *UI) Draw(win *opengl.Window) {
-	ui.updateMatrix()
-	win.SetComposeMethod(pixel.ComposeOver)
-	win.SetMatrix(ui.matrix)
+	defer ui.recoverError()
+	ui.makeCurrent()
 
 	// imgui draws things from top-left as 0,0 where Pixel draws from bottom-left as 0,0,
 	//	for drawing and handling inputs, we need to "flip" imgui.
@@ -144,76 +728,231 @@ func (ui *UI) Draw(win *opengl.Window) {
 
 	// Tell imgui to render and get the resulting draw data
 	imgui.Render()
+	ui.inFrame = false
+	ui.updateCursor()
+
+	ui.drawTo(win, true)
+}
+
+// drawTarget is what the UI is drawn to: the window, or a canvas for Capture.
+type drawTarget interface {
+	pixel.ComposeTarget
+	Smooth() bool
+	SetSmooth(smooth bool)
+}
+
+// drawTo draws the rendered imgui draw data to the target. With reuse, the triangles of the last
+//
+//	frame are drawn again if the idle throttle finds nothing changed.
+func (ui *UI) drawTo(t drawTarget, reuse bool) {
+	t.SetComposeMethod(ui.compose)
+	t.SetMatrix(ui.matrix)
+	defer t.SetSmooth(t.Smooth())
+	t.SetSmooth(ui.smooth)
+
 	data := imgui.RenderedDrawData()
 
+	textures := ui.atlas.Textures()
+	if len(textures) == 0 {
+		// The atlas was never packed, there is nothing the triangles could sample.
+		t.SetMatrix(ui.restoreMatrix)
+		t.SetComposeMethod(ui.restoreCompose)
+		return
+	}
+
+	if reuse && ui.reuseTriangles(data) {
+		// Nothing changed since the last frame, its triangles are still valid.
+		ui.drawCalls = 0
+		ui.drawRuns(t)
+		t.SetMatrix(ui.restoreMatrix)
+		t.SetComposeMethod(ui.restoreCompose)
+		return
+	}
+
 	// Since we have to redraw all of the triangles every frame,
 	//	only resize the triangles list when we need to, and truncate
 	//	it right before we draw (to get rid of any extra triangles).
 	totalTris := 0
 
-	// In each command, there is a vertex buffer that holds all of the vertices to draw;
-	// 	there's also an index buffer which stores the indices into the vertex buffer that should
-	//	be draw together. The vertex buffer is shared between multiple commands.
-	vertexSize, posOffset, uvOffset, colOffset := imgui.VertexBufferLayout()
-	indexSize := imgui.IndexBufferLayout()
-	for _, cmds := range data.CommandLists() {
-		var indexBufferOffset uintptr
-		start, _ := cmds.VertexBuffer()
-		idxStart, _ := cmds.IndexBuffer()
-
-		for _, cmd := range cmds.Commands() {
-			if cmd.HasUserCallback() {
-				cmd.CallUserCallback(cmds)
-			} else {
-				count := cmd.ElementCount()
-				iStart := totalTris
-				totalTris += count
-
-				if ui.shaderTris.Len() < totalTris {
-					ui.shaderTris.SetLen(totalTris)
-				}
-
-				clipRect := imguiRectToPixelRect(cmd.ClipRect()).Norm()
-				clipRect.Min = ui.matrix.Project(clipRect.Min)
-				clipRect.Max = ui.matrix.Project(clipRect.Max)
-				clipRect = clipRect.Norm()
-
-				id := uint32(cmd.TextureID())
-				spr := ui.atlas.Get(id)
-				texRect := spr.Frame()
-
-				intensity := 0.0
-				if id != ui.font.ID() {
-					intensity = 1.0
-				}
-
-				for i := 0; i < count; i++ {
-					idx := unsafe.Pointer(uintptr(idxStart) + indexBufferOffset)
-					index := *(*uint16)(idx)
-					ptr := unsafe.Pointer(uintptr(start) + (uintptr(int(index) * vertexSize)))
-					pos := *(*imgui.Vec2)(unsafe.Pointer(uintptr(ptr) + uintptr(posOffset)))
-					uv := *(*imgui.Vec2)(unsafe.Pointer(uintptr(ptr) + uintptr(uvOffset)))
-					col := *(*uint32)(unsafe.Pointer(uintptr(ptr) + uintptr(colOffset)))
-
-					position := PV(pos)
-					color := imguiColorToPixelColor(col)
-					uuvv := ui.calcData(texRect, PV(uv))
-
-					ui.shaderTris.SetPosition(iStart+i, position)
-					ui.shaderTris.SetPicture(iStart+i, uuvv, intensity)
-					ui.shaderTris.SetColor(iStart+i, pixel.ToRGBA(color))
-					ui.shaderTris.SetClipRect(iStart+i, clipRect)
-					indexBufferOffset += uintptr(indexSize)
-				}
+	texScales := ui.atlasScales(textures)
+	ui.runs = ui.runs[:0]
+	ui.drawCalls = 0
+
+	for _, batch := range ui.DrawDataSlices() {
+		if batch.Callback != nil {
+			// Callbacks draw to the window, they're skipped when drawing anywhere else.
+			win, ok := t.(*opengl.Window)
+			if !ok {
+				continue
 			}
+
+			// Everything before the callback has to be on screen before it runs.
+			ui.flush(t, totalTris)
+			totalTris = 0
+			ui.runs = ui.runs[:0]
+
+			batch.Callback(win)
+			t.SetComposeMethod(ui.compose)
+			t.SetMatrix(ui.matrix)
+			continue
+		}
+
+		count := len(batch.Positions)
+		iStart := totalTris
+		totalTris += count
+
+		if ui.shaderTris.Len() < totalTris {
+			ui.shaderTris.SetLen(totalTris)
 		}
+
+		id := uint32(batch.TextureID)
+		spr := ui.atlas.Get(id)
+		texRect := spr.Frame()
+		page := ui.pageOf(id)
+		texScale := texScales[page]
+		ui.addRun(page, totalTris)
+
+		// Font textures only carry coverage in their alpha channel, anything else is a full RGBA image.
+		intensity := 0.0
+		if !ui.alphaTex[id] {
+			intensity = 1.0
+		}
+		intensity = ui.clipIntensity(intensity, batch.ClipRect)
+
+		for i := 0; i < count; i++ {
+			pos := batch.Positions[i]
+			if ui.pixelPerfect {
+				pos = pixel.V(math.Round(pos.X), math.Round(pos.Y))
+			}
+			ui.shaderTris.SetPosition(iStart+i, pos)
+			ui.shaderTris.SetPicture(iStart+i, ui.calcData(texRect, batch.UVs[i], texScale), intensity)
+			ui.shaderTris.SetColor(iStart+i, pixel.ToRGBA(batch.Colors[i]))
+			ui.shaderTris.SetClipRect(iStart+i, batch.ClipRect)
+		}
+	}
+
+	ui.flush(t, totalTris)
+
+	t.SetMatrix(ui.restoreMatrix)
+	t.SetComposeMethod(ui.restoreCompose)
+}
+
+// Capture draws the UI of the last Draw to an offscreen canvas the size of the window and returns its
+//
+//	pixels, e.g. to save a screenshot of just the UI with PictureData.Image. Call it after Draw.
+//	Draw callbacks are skipped, as they draw to the window.
+func (ui *UI) Capture() (pixel.Picture, error) {
+	ui.makeCurrent()
+	if !imgui.RenderedDrawData().Valid() {
+		return nil, fmt.Errorf("pixelui: nothing to capture, call Capture after Draw")
+	}
+
+	canvas := opengl.NewCanvas(ui.bounds())
+	ui.drawTo(canvas, false)
+	// The triangles now hold the capture, make the next Draw rebuild them.
+	ui.drawHash = 0
+
+	// PictureData keeps Pixel's bottom-up rows, Image() flips them into a top-down image.
+	return pixel.PictureDataFromPicture(canvas), nil
+}
+
+// SetRestoreState sets the matrix and compose method Draw leaves the window with, so the UI can be
+//
+//	drawn mid-scene. Pixel's window can't report its current state, so Draw can't capture it itself.
+//	Defaults to pixel.IM and pixel.ComposeOver.
+func (ui *UI) SetRestoreState(m pixel.Matrix, compose pixel.ComposeMethod) {
+	ui.restoreMatrix = m
+	ui.restoreCompose = compose
+}
+
+// SetComposeMethod sets the compose method used while drawing the UI, pixel.ComposeOver by default.
+//
+//	UIs drawn over an opaque, cleared background can use pixel.ComposeCopy to skip blending, at the
+//	cost of all transparency: anti-aliased edges, text and translucent windows overwrite what's below.
+//	Draw switches back to the compose method set with SetRestoreState when it's done.
+func (ui *UI) SetComposeMethod(m pixel.ComposeMethod) {
+	ui.compose = m
+}
+
+// SetTextureSmooth selects linear (true) or nearest (false) filtering of the atlas texture while
+//
+//	drawing the UI. Pixel filters textures as the window does when drawing, so this overrides the
+//	window's smoothing during Draw only. Defaults to the window's setting when the UI was created.
+func (ui *UI) SetTextureSmooth(smooth bool) {
+	ui.smooth = smooth
+}
+
+// SetSRGB tells the UI whether the window's framebuffer is sRGB, blending in linear space.
+//
+//	imgui's colors and the atlas are sRGB, so with this enabled they are converted to linear before
+//	blending and the framebuffer converts the result back, keeping text weight and colors as designed.
+func (ui *UI) SetSRGB(enabled bool) {
+	ui.srgb = 0
+	if enabled {
+		ui.srgb = 1
 	}
+}
+
+// SetClipRounding rounds the corners of imgui's clip rects by radius imgui pixels (rounded to whole
+//
+//	pixels), e.g. set to the style's window rounding so content scrolled into a window's corners
+//	doesn't bleed past its rounded background. Clip rects covering the whole display are left square.
+//	0, the default, clips square.
+func (ui *UI) SetClipRounding(radius float64) {
+	ui.clipRounding = math.Max(0, math.Round(radius))
+	ui.drawHash = 0
+}
 
-	ui.shaderTris.SetLen(totalTris)
+// clipIntensity adds the corner radius for the clip rect to the vertex intensity.
+//
+//	Pixel's triangles have no spare vertex attribute, so the shader decodes the radius from the
+//	intensity: 0 or 1 for the texture kind plus twice the radius.
+func (ui *UI) clipIntensity(intensity float64, clip pixel.Rect) float64 {
+	size := ui.displaySize()
+	if clip.Min.X <= 0 && clip.Min.Y <= 0 && clip.Max.X >= size.X && clip.Max.Y >= size.Y {
+		return intensity
+	}
+	return intensity + 2*math.Min(ui.clipRounding, math.Floor(math.Min(clip.W(), clip.H())/2))
+}
+
+// flush draws the first n vertices of the triangle buffer to the target.
+func (ui *UI) flush(t drawTarget, n int) {
+	if n == 0 {
+		return
+	}
+	ui.shaderTris.SetLen(n)
 	ui.shaderTris.CopyVertices()
-	win.MakePicture(ui.atlas.Textures()[0]).Draw(win.MakeTriangles(ui.shaderTris))
+	ui.drawRuns(t)
+}
+
+// atlasPicture returns the target's picture of the given atlas texture.
+//
+//	The picture is cached and only re-created when the atlas texture or the target changes.
+func (ui *UI) atlasPicture(t drawTarget, page int) pixel.TargetPicture {
+	if len(ui.pictures) <= page {
+		ui.pictures = append(ui.pictures, make([]pagePicture, page+1-len(ui.pictures))...)
+	}
+	p := &ui.pictures[page]
+
+	var tex pixel.Picture = ui.atlas.Textures()[page]
+	if p.picture == nil || p.src != tex {
+		p.picture = t.MakePicture(tex)
+		p.src = tex
+		p.target = t
+	} else if p.target != t {
+		// Share the already uploaded texture with the new target instead of uploading it again.
+		p.picture = t.MakePicture(p.picture)
+		p.target = t
+	}
+	return p.picture
+}
 
-	win.SetMatrix(pixel.IM)
+// invalidatePicture drops the cached atlas pictures so they're re-created on the next Draw.
+func (ui *UI) invalidatePicture() {
+	ui.pictures = ui.pictures[:0]
+	clear(ui.pages)
+	// The texture coordinates in the triangles may be stale now too.
+	ui.drawHash = 0
 }
 
 // recip returns the reciprocal of the given number.
@@ -222,17 +961,45 @@ func recip(m float64) float64 {
 }
 
 // calcData scales the incoming sprite uv to the proper sub-sprite in the packed atlas.
-func (ui *UI) calcData(frame pixel.Rect, uuvv pixel.Vec) (pic pixel.Vec) {
-	return uuvv.ScaledXY(frame.Size()).Add(frame.Min).ScaledXY(ui.atlas.Textures()[0].Bounds().Size().Map(recip))
+//
+//	texScale is the reciprocal of the atlas texture size, see atlasScales.
+func (ui *UI) calcData(frame pixel.Rect, uuvv pixel.Vec, texScale pixel.Vec) (pic pixel.Vec) {
+	return uuvv.ScaledXY(frame.Size()).Add(frame.Min).ScaledXY(texScale)
+}
+
+// atlasScales returns the reciprocal of the size of each atlas texture, computed once per Draw rather
+//
+//	than for every vertex. The returned slice is reused by the next call.
+func (ui *UI) atlasScales(textures []*pixel.PictureData) []pixel.Vec {
+	ui.scales = ui.scales[:0]
+	for _, tex := range textures {
+		ui.scales = append(ui.scales, tex.Bounds().Size().Map(recip))
+	}
+	return ui.scales
 }
 
 // imguiColorToPixelColor Converts the imgui color to a Pixel color.
-func imguiColorToPixelColor(c uint32) color.RGBA {
-	// ABGR -> RGBA
-	return color.RGBA{
-		A: uint8((c >> 24) & 0xFF),
-		B: uint8((c >> 16) & 0xFF),
-		G: uint8((c >> 8) & 0xFF),
-		R: uint8(c & 0xFF),
+//
+//	imgui's colors aren't premultiplied by their alpha, hence NRGBA. imgui packs colors with shifts,
+//	so this only depends on its channel layout (see the pixelui_bgra build tag), not on the platform's
+//	byte order.
+func imguiColorToPixelColor(c uint32) color.NRGBA {
+	return color.NRGBA{
+		A: uint8((c >> colorShiftA) & 0xFF),
+		B: uint8((c >> colorShiftB) & 0xFF),
+		G: uint8((c >> colorShiftG) & 0xFF),
+		R: uint8((c >> colorShiftR) & 0xFF),
 	}
 }
+
+// pixelColorToImguiColor Converts a Go color to an imgui packed color, the inverse of imguiColorToPixelColor.
+func pixelColorToImguiColor(c color.Color) uint32 {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return uint32(n.A)<<colorShiftA | uint32(n.B)<<colorShiftB | uint32(n.G)<<colorShiftG | uint32(n.R)<<colorShiftR
+}
+
+// Channel shifts of imgui's packed colors shared by every layout.
+const (
+	colorShiftG = 8
+	colorShiftA = 24
+)
@@ -0,0 +1,750 @@
+package pixelui
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/gopxl/pixel/v2/ext/atlas"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// hasGL is true if the tests run with GLFW, the tests that need a window skip themselves otherwise.
+var hasGL bool
+
+func TestMain(m *testing.M) {
+	// GLFW needs an X11 or Wayland display on Linux, e.g. in CI only the headless tests run.
+	if runtime.GOOS == "linux" && os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		os.Exit(m.Run())
+	}
+	code := 0
+	opengl.Run(func() {
+		hasGL = true
+		code = m.Run()
+	})
+	os.Exit(code)
+}
+
+// newTestWindow opens a hidden 200x100 window for the tests that need GL, skipping them without one.
+func newTestWindow(tb testing.TB) *opengl.Window {
+	tb.Helper()
+	if !hasGL {
+		tb.Skip("GLFW is not available")
+	}
+	win, err := opengl.NewWindow(opengl.WindowConfig{Bounds: pixel.R(0, 0, 200, 100), Invisible: true})
+	if err != nil {
+		tb.Skipf("can't open a window: %v", err)
+	}
+	tb.Cleanup(win.Destroy)
+	return win
+}
+
+// newTestUI creates a headless UI covering a 200x100 window, driven by the returned TestInput.
+func newTestUI(t testing.TB) (*UI, *TestInput) {
+	t.Helper()
+	return newTestUIWithOptions(t, Options{Flags: OWN_ATLAS})
+}
+
+// newTestUIWithOptions is newTestUI for a UI created with the given options.
+func newTestUIWithOptions(t testing.TB, opts Options) (*UI, *TestInput) {
+	t.Helper()
+	input := NewTestInput()
+	input.SetBounds(pixel.R(0, 0, 200, 100))
+	ui, err := NewHeadless(input, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keep the tests from writing imgui.ini into the package directory.
+	ui.SetIniFilename("")
+	t.Cleanup(func() {
+		runtime.SetFinalizer(ui, nil)
+		ui.destroy()
+	})
+	return ui, input
+}
+
+func TestFrameDelta(t *testing.T) {
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		timer time.Time
+		now   time.Time
+		want  float32
+	}{
+		{"first frame", time.Time{}, last, 0.001},
+		{"zero", last, last, 0.001},
+		{"negative", last, last.Add(-time.Second), 0.001},
+		{"normal", last, last.Add(16 * time.Millisecond), 0.016},
+		{"at max", last, last.Add(defaultMaxDelta), float32(defaultMaxDelta.Seconds())},
+		{"above max", last, last.Add(5 * time.Second), float32(defaultMaxDelta.Seconds())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ui := &UI{timer: tt.timer, maxDelta: defaultMaxDelta}
+			if got := ui.frameDelta(tt.now); got != tt.want {
+				t.Errorf("frameDelta = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameTiming(t *testing.T) {
+	ui, _ := newTestUI(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ui.SetTimeSource(func() time.Time { return now })
+
+	for i, step := range []time.Duration{0, 20 * time.Millisecond, 50 * time.Millisecond} {
+		now = now.Add(step)
+		ui.NewFrame()
+		want := float32(step.Seconds())
+		if i == 0 {
+			want = 0.001
+		}
+		if got := ui.DeltaTime(); got != want {
+			t.Errorf("frame %d: delta = %v, want %v", i+1, got, want)
+		}
+		if got := ui.FrameCount(); got != i+1 {
+			t.Errorf("frame %d: frame count = %d", i+1, got)
+		}
+		ui.DiscardFrame()
+	}
+}
+
+func TestFramerateAndMetrics(t *testing.T) {
+	ui, _ := newTestUI(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ui.SetTimeSource(func() time.Time {
+		now = now.Add(20 * time.Millisecond)
+		return now
+	})
+	if ui.Framerate() != 0 {
+		t.Errorf("framerate = %v before the first frame", ui.Framerate())
+	}
+
+	for i := 0; i < 10; i++ {
+		renderWindow(ui, func() {
+			imgui.Text("HUD")
+		})
+	}
+	// imgui averages the frame times, the first frame counts as 1 ms and the rest 20 ms each.
+	if fps, want := ui.Framerate(), 20/(19*0.02+0.001); math.Abs(float64(fps)-want) > 0.01 {
+		t.Errorf("framerate = %v after 20 ms frames, want %v", fps, want)
+	}
+	if ui.MetricsRenderVertices() == 0 || ui.MetricsRenderIndices() == 0 {
+		t.Errorf("rendered %d vertices, %d indices", ui.MetricsRenderVertices(), ui.MetricsRenderIndices())
+	}
+	// imgui's implicit debug window is active but not drawn.
+	if ui.MetricsRenderWindows() != 1 || ui.MetricsActiveWindows() != 2 {
+		t.Errorf("%d windows rendered, %d active, want 1 and 2", ui.MetricsRenderWindows(), ui.MetricsActiveWindows())
+	}
+}
+
+func TestNewFrameTwice(t *testing.T) {
+	ui, _ := newTestUI(t)
+	ui.NewFrame()
+	imgui.Begin("Test")
+	imgui.End()
+	// The first frame is discarded without being drawn, instead of imgui asserting.
+	ui.NewFrame()
+	defer ui.DiscardFrame()
+	if ui.FrameCount() != 2 || !ui.inFrame {
+		t.Errorf("after two NewFrame calls frame count = %d, in frame = %v", ui.FrameCount(), ui.inFrame)
+	}
+}
+
+func TestTimeSource(t *testing.T) {
+	ui, _ := newTestUI(t)
+	const step = time.Second / 60
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ui.SetTimeSource(func() time.Time {
+		now = now.Add(step)
+		return now
+	})
+
+	// The first frame has nothing to measure from.
+	ui.NewFrame()
+	if got := ui.DeltaTime(); got != 0.001 {
+		t.Errorf("delta of the first frame = %v, want 0.001", got)
+	}
+	for i := 0; i < 5; i++ {
+		ui.NewFrame()
+		if got := ui.DeltaTime(); got != float32(step.Seconds()) {
+			t.Errorf("delta of frame %d = %v, want %v", i+2, got, float32(step.Seconds()))
+		}
+	}
+	ui.DiscardFrame()
+}
+
+func TestTwoUIs(t *testing.T) {
+	small, _ := newTestUI(t)
+	input := NewTestInput()
+	input.SetBounds(pixel.R(0, 0, 300, 150))
+	big, err := NewHeadless(input, nil, Options{Flags: OWN_ATLAS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	big.SetIniFilename("")
+	t.Cleanup(func() {
+		runtime.SetFinalizer(big, nil)
+		big.destroy()
+	})
+
+	uis := []*UI{small, big}
+	sizes := []imgui.Vec2{IV(50, 40), IV(120, 90)}
+	for frame := 0; frame < 2; frame++ {
+		for i, ui := range uis {
+			ui.NewFrame()
+			imgui.SetNextWindowPos(imgui.Vec2{})
+			imgui.SetNextWindowSize(sizes[i])
+			imgui.Begin("Window")
+			imgui.End()
+			imgui.Render()
+			ui.inFrame = false
+		}
+	}
+
+	for i, ui := range uis {
+		ui.makeCurrent()
+		data := imgui.RenderedDrawData()
+		if got, want := data.DisplaySize(), IVec(ui.displaySize()); got != want {
+			t.Errorf("UI %d rendered a display of %v, want %v", i, got, want)
+		}
+		if n := len(data.CommandLists()); n != 1 {
+			t.Errorf("UI %d rendered %d windows, want its own one", i, n)
+		}
+		// Every vertex lies in the UI's own window, give or take its anti-aliased border.
+		for _, batch := range ui.DrawDataSlices() {
+			for _, p := range batch.Positions {
+				if p.X > float64(sizes[i].X)+2 || p.Y > float64(sizes[i].Y)+2 {
+					t.Fatalf("UI %d has a vertex at %v outside its %v window", i, p, sizes[i])
+				}
+			}
+		}
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	ui, _ := newTestUI(t)
+	var msgs []string
+	ui.SetErrorHandler(func(msg string) {
+		msgs = append(msgs, msg)
+	})
+	defer ui.SetErrorHandler(nil)
+
+	ui.NewFrame()
+	// An assertion raised while building the UI reaches the handler instead of panicking.
+	imgui.End()
+	ui.DiscardFrame()
+
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "Calling End() too many times!") {
+		t.Errorf("error handler got %q, want the End assertion", msgs)
+	}
+}
+
+func TestSafeFramePanicInWindow(t *testing.T) {
+	ui, _ := newTestUI(t)
+	err := ui.SafeFrame(nil, func() {
+		imgui.Begin("Outer")
+		imgui.Begin("Inner")
+		panic("boom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("SafeFrame returned %v, want the panic", err)
+	}
+
+	// The windows were ended with the frame, the next one starts cleanly.
+	ui.NewFrame()
+	imgui.Begin("Outer")
+	imgui.End()
+	ui.DiscardFrame()
+}
+
+func TestNewWithError(t *testing.T) {
+	win := newTestWindow(t)
+	ui, err := NewWithError(win, nil, OWN_ATLAS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.SetFinalizer(ui, nil)
+	ui.SetIniFilename("")
+	ui.destroy()
+
+	// A broken shader is an error, not a panic on the main thread.
+	if err := compileShader(strings.Replace(uiShader, "void main() {", "void main() { broken", 1)); err == nil {
+		t.Error("compileShader accepted a broken shader")
+	}
+}
+
+// countingTarget counts the pictures the UI makes for it, it can't draw.
+type countingTarget struct {
+	drawTarget
+	made int
+}
+
+func (c *countingTarget) MakePicture(p pixel.Picture) pixel.TargetPicture {
+	c.made++
+	return countedPicture{p}
+}
+
+type countedPicture struct {
+	pixel.Picture
+}
+
+func (countedPicture) Draw(pixel.TargetTriangles) {}
+
+// stateTarget is a drawTarget that keeps the state the UI sets on it, it can't draw.
+type stateTarget struct {
+	countingTarget
+	matrix  pixel.Matrix
+	compose pixel.ComposeMethod
+	smooth  bool
+	// smoothed and composed record every SetSmooth and SetComposeMethod call.
+	smoothed []bool
+	composed []pixel.ComposeMethod
+}
+
+func (s *stateTarget) SetMatrix(m pixel.Matrix) { s.matrix = m }
+func (s *stateTarget) Smooth() bool             { return s.smooth }
+
+func (s *stateTarget) SetComposeMethod(c pixel.ComposeMethod) {
+	s.compose = c
+	s.composed = append(s.composed, c)
+}
+
+func (s *stateTarget) SetSmooth(smooth bool) {
+	s.smooth = smooth
+	s.smoothed = append(s.smoothed, smooth)
+}
+
+func TestTextureSmoothRestored(t *testing.T) {
+	ui, _ := newTestUI(t)
+	target := &stateTarget{smooth: true}
+	ui.SetTextureSmooth(false)
+
+	// A frame without windows has no triangles to draw, which doesn't need GL.
+	ui.NewFrame()
+	imgui.Render()
+	ui.inFrame = false
+	ui.drawTo(target, false)
+
+	if want := []bool{false, true}; !reflect.DeepEqual(target.smoothed, want) {
+		t.Errorf("the target was smoothed %v while drawing, want %v", target.smoothed, want)
+	}
+}
+
+func TestDrawRestoresState(t *testing.T) {
+	ui, _ := newTestUI(t)
+	scene := pixel.IM.Scaled(pixel.ZV, 2).Moved(pixel.V(30, 10))
+	ui.SetRestoreState(scene, pixel.ComposeIn)
+	target := &stateTarget{}
+
+	ui.NewFrame()
+	imgui.Render()
+	ui.inFrame = false
+	ui.drawTo(target, false)
+
+	if target.matrix != scene || target.compose != pixel.ComposeIn {
+		t.Errorf("Draw left the matrix %v and compose method %v, want %v and %v",
+			target.matrix, target.compose, scene, pixel.ComposeIn)
+	}
+}
+
+func TestSetComposeMethod(t *testing.T) {
+	ui, _ := newTestUI(t)
+	ui.SetComposeMethod(pixel.ComposePlus)
+	ui.SetRestoreState(pixel.IM, pixel.ComposeOver)
+	target := &stateTarget{}
+
+	ui.NewFrame()
+	imgui.Render()
+	ui.inFrame = false
+	ui.drawTo(target, false)
+
+	if want := []pixel.ComposeMethod{pixel.ComposePlus, pixel.ComposeOver}; !reflect.DeepEqual(target.composed, want) {
+		t.Errorf("the target's compose methods while drawing = %v, want %v", target.composed, want)
+	}
+}
+
+func TestComposeCopy(t *testing.T) {
+	ui, _ := newTestUIWithOptions(t, Options{Flags: OWN_ATLAS, ComposeMethod: pixel.ComposeCopy})
+	ui.SetRestoreState(pixel.IM, pixel.ComposeOver)
+	target := &stateTarget{}
+
+	ui.NewFrame()
+	imgui.Render()
+	ui.inFrame = false
+	ui.drawTo(target, false)
+
+	if want := []pixel.ComposeMethod{pixel.ComposeCopy, pixel.ComposeOver}; !reflect.DeepEqual(target.composed, want) {
+		t.Errorf("the target's compose methods while drawing = %v, want %v", target.composed, want)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	headless, _ := newTestUI(t)
+	if _, err := headless.Capture(); err == nil {
+		t.Error("Capture succeeded before anything was drawn")
+	}
+
+	ui, win := newTestGLUI(t)
+	magenta := imgui.Vec4{X: 1, Z: 1, W: 1}
+	// New windows are hidden in their first frame.
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		imgui.ShowDemoWindow(nil)
+		// A magenta title bar across the top of the window, above the demo.
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(200, 30))
+		imgui.SetNextWindowFocus()
+		imgui.PushStyleColor(imgui.StyleColorTitleBgActive, magenta)
+		imgui.Begin("Test")
+		imgui.End()
+		imgui.PopStyleColor()
+		ui.Draw(win)
+	}
+
+	pic, err := ui.Capture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pixel.PictureDataFromPicture(pic)
+	if data.Bounds() != win.Bounds() {
+		t.Fatalf("captured %v, want the window's %v", data.Bounds(), win.Bounds())
+	}
+	// Pixel's y axis points up, the title bar is in the top rows and the image isn't flipped.
+	if c := data.Color(pixel.V(150, 95)); c.R < 0.9 || c.G > 0.1 || c.B < 0.9 {
+		t.Errorf("the top of the capture is %v, want the magenta title bar", c)
+	}
+	if c := data.Image().At(150, 5); c != (color.RGBA{R: 255, B: 255, A: 255}) {
+		t.Errorf("the top of the captured image is %v, want the magenta title bar", c)
+	}
+}
+
+func TestSRGBText(t *testing.T) {
+	ui, win := newTestGLUI(t)
+	// render returns how many pixels the text covers and the background's gray level.
+	render := func(srgb bool) (covered int, background uint8) {
+		ui.SetSRGB(srgb)
+		// New windows are hidden in their first frame.
+		for i := 0; i < 2; i++ {
+			win.Clear(color.Black)
+			ui.NewFrame()
+			imgui.SetNextWindowPos(imgui.Vec2{})
+			imgui.SetNextWindowSize(IV(200, 100))
+			imgui.PushStyleColor(imgui.StyleColorWindowBg, imgui.Vec4{X: 0.5, Y: 0.5, Z: 0.5, W: 1})
+			imgui.BeginV("Test", nil, imgui.WindowFlagsNoDecoration)
+			imgui.Text("The quick brown fox")
+			imgui.End()
+			imgui.PopStyleColor()
+			ui.Draw(win)
+		}
+		pixels := win.Canvas().Pixels()
+		// The window's bottom-right corner has no text.
+		background = pixels[len(pixels)-4]
+		for i := 0; i < len(pixels); i += 4 {
+			if pixels[i] > background {
+				covered++
+			}
+		}
+		return covered, background
+	}
+
+	plain, plainBg := render(false)
+	linear, linearBg := render(true)
+	if plain == 0 || linear == 0 {
+		t.Fatalf("the text covers %d pixels, %d with sRGB", plain, linear)
+	}
+	if plainBg == linearBg && plain == linear {
+		t.Errorf("sRGB didn't change the mid-gray background (%d) or the text's coverage (%d pixels)", plainBg, plain)
+	}
+}
+
+func TestSetDisplaySize(t *testing.T) {
+	ui, input := newTestUI(t)
+	ui.SetDisplaySize(pixel.V(320, 240))
+	// The button's center at imgui's (260, 195), stretched over the 200x100 window.
+	input.MoveMouse(pixel.V(260*200.0/320, 100-195*100.0/240))
+
+	var min, max imgui.Vec2
+	var hovered bool
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(320, 240))
+		imgui.Begin("Test")
+		imgui.SetCursorPos(IV(240, 180))
+		imgui.ButtonV("Button", IV(40, 30))
+		min, max = imgui.ItemRectMin(), imgui.ItemRectMax()
+		hovered = imgui.IsItemHovered()
+		imgui.End()
+		imgui.Render()
+		ui.inFrame = false
+	}
+
+	if got := imgui.RenderedDrawData().DisplaySize(); got != IV(320, 240) {
+		t.Errorf("display size = %v, want 320x240", got)
+	}
+	// Past the window's 200x100 pixels, inside the virtual resolution.
+	if min != IV(240, 180) || max != IV(280, 210) {
+		t.Errorf("button laid out at %v-%v, want (240, 180)-(280, 210)", min, max)
+	}
+	if !hovered {
+		t.Error("the mouse over the stretched button doesn't hover it")
+	}
+}
+
+func TestNoDefaultFont(t *testing.T) {
+	ui, _ := newTestUIWithOptions(t, Options{Flags: OWN_ATLAS | NO_DEFAULT_FONT})
+	if n := len(ui.atlas.Textures()); n == 0 {
+		t.Fatal("the atlas has no texture without the default font")
+	}
+
+	target := &stateTarget{}
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		imgui.Render()
+		ui.inFrame = false
+		ui.drawTo(target, false)
+	}
+}
+
+func TestAtlasPictureCache(t *testing.T) {
+	ui, _ := newTestUI(t)
+	target := &countingTarget{}
+
+	first := ui.atlasPicture(target, 0)
+	if again := ui.atlasPicture(target, 0); again != first || target.made != 1 {
+		t.Errorf("the picture was made %d times for two frames, want once", target.made)
+	}
+
+	// Packing a new image changes the atlas texture, the picture has to be made again.
+	ui.AddImage(testPicture(4, 4, 1))
+	if ui.atlasPicture(target, 0); target.made != 2 {
+		t.Errorf("the picture was made %d times after repacking, want twice", target.made)
+	}
+}
+
+func TestDeterministicAtlas(t *testing.T) {
+	// add registers images of different sizes, returning where each one was packed.
+	add := func() []pixel.Rect {
+		ui, _ := newTestUIWithOptions(t, Options{Flags: DETERMINISTIC_ATLAS})
+		var images []Image
+		for i := 1; i <= 8; i++ {
+			images = append(images, ui.AddImage(testPicture(4*i, 20-2*i, uint8(i))))
+		}
+		frames := make([]pixel.Rect, len(images))
+		for i, img := range images {
+			frame, ok := ui.textureFrame(img.ID())
+			if !ok {
+				t.Fatalf("image %d isn't in the atlas", i+1)
+			}
+			frames[i] = frame
+		}
+		return frames
+	}
+
+	first, second := add(), add()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("image %d was packed at %v, then at %v", i+1, first[i], second[i])
+		}
+	}
+}
+
+func TestAtlasScalesAllocs(t *testing.T) {
+	ui, _ := newTestUI(t)
+	textures := ui.atlas.Textures()
+	ui.atlasScales(textures)
+	if allocs := testing.AllocsPerRun(100, func() { ui.atlasScales(textures) }); allocs != 0 {
+		t.Errorf("atlasScales allocated %v times, want 0", allocs)
+	}
+}
+
+func TestPackGroup(t *testing.T) {
+	ui, _ := newTestUI(t)
+	target := &countingTarget{}
+	ui.atlasPicture(target, 0)
+
+	group := ui.Group()
+	tex := group.AddImage(image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	ui.Pack()
+	if page := ui.pageOf(tex.ID()); page != 0 {
+		t.Errorf("the sprite was packed into texture %d, want the UI's texture 0", page)
+	}
+	if ui.atlasPicture(target, 0); target.made != 2 {
+		t.Errorf("the picture was made %d times after Pack, want twice", target.made)
+	}
+}
+
+func TestOwnAtlas(t *testing.T) {
+	shared := &atlas.Atlas{}
+	sprite := shared.AddImage(image.NewRGBA(image.Rect(0, 0, 30, 20)))
+	shared.Pack()
+	frame := sprite.Frame()
+
+	ui, err := NewHeadless(NewTestInput(), shared, Options{Flags: OWN_ATLAS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui.SetIniFilename("")
+	t.Cleanup(func() {
+		runtime.SetFinalizer(ui, nil)
+		ui.destroy()
+	})
+	if ui.Atlas() == shared {
+		t.Fatal("the UI packs into the caller's atlas")
+	}
+
+	ui.AddImage(testPicture(64, 64, 1))
+	if err := ui.rebakeFonts(); err != nil {
+		t.Fatal(err)
+	}
+	if got := shared.Get(sprite.ID()).Frame(); got != frame {
+		t.Errorf("the caller's sprite moved from %v to %v", frame, got)
+	}
+	if n := len(shared.Textures()); n != 1 {
+		t.Errorf("the caller's atlas has %d textures, want its own one", n)
+	}
+}
+
+// newTestGLUI creates a UI drawing to a hidden window, skipping the test without GL.
+func newTestGLUI(tb testing.TB) (*UI, *opengl.Window) {
+	tb.Helper()
+	win := newTestWindow(tb)
+	ui, err := NewWithError(win, nil, OWN_ATLAS)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	ui.SetIniFilename("")
+	tb.Cleanup(func() {
+		runtime.SetFinalizer(ui, nil)
+		ui.destroy()
+	})
+	return ui, win
+}
+
+func BenchmarkDraw(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		miss bool
+	}{
+		{"cache hit", false},
+		{"cache miss", true},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			ui, win := newTestGLUI(b)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if bc.miss {
+					ui.invalidatePicture()
+				}
+				ui.NewFrame()
+				imgui.ShowDemoWindow(nil)
+				ui.Draw(win)
+			}
+		})
+	}
+}
+
+func TestDrawRuns(t *testing.T) {
+	ui, _ := newTestUI(t)
+	images := make([]Image, 8)
+	for i := range images {
+		images[i] = ui.AddImage(testPicture(16, 16, uint8(i)))
+	}
+	renderWindow(ui, func() {
+		imgui.Text("Font")
+		for _, img := range images {
+			ui.Image(img)
+			imgui.Text("between the images")
+		}
+	})
+
+	// The font and the images alternate, but share the atlas' only texture: one run, one draw call.
+	end := 0
+	for _, batch := range ui.DrawDataSlices() {
+		end += len(batch.Positions)
+		ui.addRun(ui.pageOf(uint32(batch.TextureID)), end)
+	}
+	if want := []pageRun{{page: 0, end: end}}; !reflect.DeepEqual(ui.runs, want) {
+		t.Errorf("runs = %v, want %v", ui.runs, want)
+	}
+
+	// Commands on different pages stay in imgui's order, only neighbours merge.
+	ui.runs = ui.runs[:0]
+	for i, page := range []int{0, 0, 1, 1, 0} {
+		ui.addRun(page, i+1)
+	}
+	if want := []pageRun{{0, 2}, {1, 4}, {0, 5}}; !reflect.DeepEqual(ui.runs, want) {
+		t.Errorf("runs = %v, want %v", ui.runs, want)
+	}
+}
+
+func BenchmarkDrawImages(b *testing.B) {
+	ui, win := newTestGLUI(b)
+	images := make([]Image, 64)
+	for i := range images {
+		images[i] = ui.AddImage(testPicture(16, 16, uint8(i)))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ui.NewFrame()
+		imgui.Begin("Images")
+		for _, img := range images {
+			imgui.Text("Image")
+			imgui.SameLine()
+			ui.Image(img)
+		}
+		imgui.End()
+		ui.Draw(win)
+	}
+	b.ReportMetric(float64(ui.DrawCalls()), "drawcalls")
+}
+
+func TestResetKeepsSettings(t *testing.T) {
+	ui, input := newTestUI(t)
+	ini := filepath.Join(t.TempDir(), "imgui.ini")
+	ui.SetIniFilename(ini)
+	ui.SetMouseDrawCursor(true)
+	ui.SetWindowsClampToViewport(true)
+	ui.clampPos["Test"] = imgui.Vec2{X: 10, Y: 10}
+	ui.NewFrame()
+	ui.OpenPopupAt("Popup", pixel.V(20, 20))
+	ui.DiscardFrame()
+
+	if err := ui.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ui.clampPos) != 0 || len(ui.popupPos) != 0 {
+		t.Errorf("positions queued before Reset survived: %v, %v", ui.clampPos, ui.popupPos)
+	}
+
+	// The software cursor is the only thing drawn in a frame without windows.
+	input.MoveMouse(pixel.V(50, 50))
+	ui.NewFrame()
+	imgui.Render()
+	ui.inFrame = false
+	if ui.MetricsRenderVertices() == 0 {
+		t.Error("the software cursor isn't drawn after Reset")
+	}
+
+	// The context destroyed by the next Reset saves its settings to the ini file set before.
+	if err := ui.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(ini); err != nil {
+		t.Errorf("the settings weren't saved to the ini file: %v", err)
+	}
+	ui.SetIniFilename("")
+}
@@ -0,0 +1,220 @@
+package pixelui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// Clipper draws a large list of evenly spaced items, calling draw only with the range [start, end) of
+//
+//	items that are visible. Pass an itemHeight <= 0 to let imgui measure the first item.
+func (ui *UI) Clipper(itemCount int, itemHeight float32, draw func(start, end int)) {
+	var clipper imgui.ListClipper
+	if itemHeight > 0 {
+		clipper.BeginV(itemCount, itemHeight)
+	} else {
+		clipper.Begin(itemCount)
+	}
+	defer clipper.End()
+
+	for clipper.Step() {
+		draw(clipper.DisplayStart, clipper.DisplayEnd)
+	}
+}
+
+// hoverTimer tracks how long the item shown with TooltipDelayed has been hovered.
+type hoverTimer struct {
+	key     string
+	frame   int
+	elapsed time.Duration
+}
+
+// TooltipDelayed shows text as a tooltip for the last item once it has been hovered for the given delay
+func (ui *UI) TooltipDelayed(text string, delay time.Duration) {
+	if !imgui.IsItemHovered() {
+		return
+	}
+
+	// Only one item can be hovered, so a single timer does. Start over when a different item
+	//	is hovered or a frame passed without this one being hovered.
+	key := fmt.Sprint(text, imgui.ItemRectMin())
+	if ui.hover.key != key || ui.hover.frame < ui.frames-1 {
+		ui.hover = hoverTimer{key: key}
+	}
+	if ui.hover.frame != ui.frames {
+		ui.hover.elapsed += time.Duration(float64(ui.delta) * float64(time.Second))
+		ui.hover.frame = ui.frames
+	}
+
+	if ui.hover.elapsed >= delay {
+		imgui.SetTooltip(text)
+	}
+}
+
+// PlotOptions configures PlotLines and PlotHistogram.
+//
+//	Offset: index of the first value to plot, the values wrap around (for ring buffers).
+//	Overlay: text drawn over the plot.
+//	ScaleMin, ScaleMax: range of the plot. If they are equal, the range fits the values.
+//	Size: size of the plot, a zero component uses imgui's default.
+type PlotOptions struct {
+	Offset   int
+	Overlay  string
+	ScaleMin float32
+	ScaleMax float32
+	Size     pixel.Vec
+}
+
+// scale returns the plot range, with imgui's FLT_MAX asking it to fit the values.
+func (opts PlotOptions) scale() (min, max float32) {
+	if opts.ScaleMin == opts.ScaleMax {
+		return math.MaxFloat32, math.MaxFloat32
+	}
+	return opts.ScaleMin, opts.ScaleMax
+}
+
+// offset returns the offset wrapped into [0, n), imgui indexes the values with it unchecked.
+func (opts PlotOptions) offset(n int) int {
+	return (opts.Offset%n + n) % n
+}
+
+// PlotLines plots the values as a line graph
+func (ui *UI) PlotLines(label string, values []float32, opts PlotOptions) {
+	if len(values) == 0 {
+		return
+	}
+	min, max := opts.scale()
+	imgui.PlotLinesV(label, values, opts.offset(len(values)), opts.Overlay, min, max, IVec(opts.Size))
+}
+
+// PlotHistogram plots the values as a histogram
+func (ui *UI) PlotHistogram(label string, values []float32, opts PlotOptions) {
+	if len(values) == 0 {
+		return
+	}
+	min, max := opts.scale()
+	imgui.PlotHistogramV(label, values, opts.offset(len(values)), opts.Overlay, min, max, IVec(opts.Size))
+}
+
+// ColorEdit edits a Go color with imgui's color editor, returning the edited color and true if it changed
+func (ui *UI) ColorEdit(label string, c color.Color) (color.Color, bool) {
+	col := colorToFloats(c)
+	if !imgui.ColorEdit4(label, &col) {
+		return c, false
+	}
+	return floatsToColor(col), true
+}
+
+// ColorPicker edits a Go color with imgui's color picker, returning the edited color and true if it changed
+func (ui *UI) ColorPicker(label string, c color.Color) (color.Color, bool) {
+	col := colorToFloats(c)
+	if !imgui.ColorPicker4(label, &col) {
+		return c, false
+	}
+	return floatsToColor(col), true
+}
+
+// colorToFloats converts a Go color to imgui's non-premultiplied float RGBA.
+func colorToFloats(c color.Color) [4]float32 {
+	v := IColor(c)
+	return [4]float32{v.X, v.Y, v.Z, v.W}
+}
+
+// floatsToColor converts imgui's float RGBA back to a Go color, clamping components imgui let out of [0, 1].
+func floatsToColor(col [4]float32) color.NRGBA {
+	channel := func(f float32) uint8 {
+		return uint8(math.Round(float64(clamp01(f)) * 255))
+	}
+	return color.NRGBA{R: channel(col[0]), G: channel(col[1]), B: channel(col[2]), A: channel(col[3])}
+}
+
+// clamp01 clamps f to [0, 1].
+func clamp01(f float32) float32 {
+	if f < 0 || math.IsNaN(float64(f)) {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+// InputText edits the Go string s, returning true if it changed this frame.
+//
+//	imgui-go grows the text buffer as needed and writes the text back into s, so any length can be
+//	typed. Pass imgui.InputTextFlagsPassword to hide the text.
+func (ui *UI) InputText(label string, s *string, flags imgui.InputTextFlags) bool {
+	changed := imgui.InputTextV(label, s, flags, nil)
+	ui.trackTextInput()
+	return changed
+}
+
+// InputTextMultiline edits the Go string s in a multi line text box of the given size like InputText.
+//
+//	A zero size uses imgui's default.
+func (ui *UI) InputTextMultiline(label string, s *string, size pixel.Vec, flags imgui.InputTextFlags) bool {
+	changed := imgui.InputTextMultilineV(label, s, IVec(size), flags, nil)
+	ui.trackTextInput()
+	return changed
+}
+
+// activeField is the rect of the text field being edited and the frame it was seen in.
+type activeField struct {
+	rect  pixel.Rect
+	frame int
+}
+
+// trackTextInput remembers the rect of the last item if it is a text field being edited.
+func (ui *UI) trackTextInput() {
+	if imgui.IsItemActive() {
+		ui.activeField = activeField{
+			rect:  pixel.Rect{Min: PV(imgui.ItemRectMin()), Max: PV(imgui.ItemRectMax())},
+			frame: ui.frames,
+		}
+	}
+}
+
+// TextInputRect returns where the text field being edited is, in Pixel coordinates, e.g. to place an
+//
+//	on-screen keyboard. imgui-go doesn't bind imgui's IME data, so only fields drawn with ui.InputText
+//	and ui.InputTextMultiline are known; it returns false while none of them is being edited.
+func (ui *UI) TextInputRect() (pixel.Rect, bool) {
+	if !ui.io.WantTextInput() || ui.activeField.frame < ui.frames-1 {
+		return pixel.ZR, false
+	}
+	r := ui.activeField.rect
+	return pixel.Rect{Min: ui.ToPixel(r.Min), Max: ui.ToPixel(r.Max)}.Norm(), true
+}
+
+// Table draws a table with a header row of the given columns and rows rows, calling cell to fill in
+//
+//	each visible cell. The table scrolls within the remaining space of the window, keeping the header
+//	in view, and only rows scrolled into view are built, so large row counts stay cheap.
+func (ui *UI) Table(id string, columns []string, rows int, cell func(row, col int)) {
+	flags := imgui.TableFlagsBorders | imgui.TableFlagsRowBg | imgui.TableFlagsScrollY
+	if len(columns) == 0 || !imgui.BeginTableV(id, len(columns), flags, imgui.Vec2{}, 0) {
+		return
+	}
+	defer imgui.EndTable()
+
+	imgui.TableSetupScrollFreeze(0, 1)
+	for _, column := range columns {
+		imgui.TableSetupColumn(column)
+	}
+	imgui.TableHeadersRow()
+
+	ui.Clipper(rows, 0, func(start, end int) {
+		for row := start; row < end; row++ {
+			imgui.TableNextRow()
+			for col := range columns {
+				imgui.TableSetColumnIndex(col)
+				cell(row, col)
+			}
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package pixelui
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gopxl/pixel/v2"
+
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// renderWindow renders two frames of a 200x100 window running fn, as imgui hides a new window for its
+//
+//	first frame. Rendering asserts if fn left imgui's Begin/End stacks unbalanced.
+func renderWindow(ui *UI, fn func()) {
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(200, 100))
+		imgui.Begin("Test")
+		fn()
+		imgui.End()
+		imgui.Render()
+		ui.inFrame = false
+	}
+}
+
+func TestTooltipDelayed(t *testing.T) {
+	ui, input := newTestUI(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ui.SetTimeSource(func() time.Time {
+		now = now.Add(100 * time.Millisecond)
+		return now
+	})
+
+	// frame renders the window with a button and returns the number of imgui windows drawn.
+	frame := func() int {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(200, 100))
+		imgui.Begin("Test")
+		imgui.Button("Button")
+		ui.TooltipDelayed("Tooltip", 250*time.Millisecond)
+		imgui.End()
+		imgui.Render()
+		ui.inFrame = false
+		input.Update()
+		return len(imgui.RenderedDrawData().CommandLists())
+	}
+	frame()
+	frame()
+
+	// Just below the window's title bar, where the button is. Each frame takes 100ms, so the delay
+	//	is over in the third hovered frame. The tooltip window shows from the frame after it is asked
+	//	for, like any new window.
+	input.MoveMouse(pixel.V(20, 65))
+	for i, want := range []int{1, 1, 1, 2, 2} {
+		if got := frame(); got != want {
+			t.Errorf("frame %d after hovering: %d windows drawn, want %d", i+1, got, want)
+		}
+	}
+
+	input.MoveMouse(pixel.V(150, 10))
+	frame()
+	if got := frame(); got != 1 {
+		t.Errorf("the tooltip is still drawn after the mouse left the button, %d windows", got)
+	}
+}
+
+func TestClipper(t *testing.T) {
+	for _, height := range []float32{0, 20} {
+		ui, _ := newTestUI(t)
+		var drawn []int
+		renderWindow(ui, func() {
+			drawn = drawn[:0]
+			ui.Clipper(100000, height, func(start, end int) {
+				for i := start; i < end; i++ {
+					drawn = append(drawn, i)
+					imgui.Text("Item")
+				}
+			})
+		})
+
+		if len(drawn) == 0 || len(drawn) > 20 {
+			t.Errorf("item height %v: drew %d of 100000 items, want only the visible ones", height, len(drawn))
+		}
+		// The ranges follow each other, starting at the top of the list.
+		for i, item := range drawn {
+			if item != i {
+				t.Errorf("item height %v: drew items %v, want the first ones in order", height, drawn)
+				break
+			}
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	ui, _ := newTestUI(t)
+	rows := make(map[int]int)
+	renderWindow(ui, func() {
+		clear(rows)
+		ui.Table("Table", []string{"A", "B", "C"}, 1000, func(row, col int) {
+			rows[row]++
+			imgui.Text("Cell")
+		})
+	})
+
+	if len(rows) == 0 || len(rows) > 20 {
+		t.Errorf("cells were drawn for %d of 1000 rows, want only the visible ones", len(rows))
+	}
+	for row, cells := range rows {
+		if cells != 3 {
+			t.Errorf("row %d has %d cells drawn, want 3", row, cells)
+		}
+	}
+}
+
+func TestPlotOffset(t *testing.T) {
+	for _, tc := range []struct{ offset, want int }{
+		{0, 0}, {3, 3}, {7, 2}, {-1, 4}, {-5, 0}, {-12, 3},
+	} {
+		if got := (PlotOptions{Offset: tc.offset}).offset(5); got != tc.want {
+			t.Errorf("offset %d of 5 values = %d, want %d", tc.offset, got, tc.want)
+		}
+	}
+
+	ui, _ := newTestUI(t)
+	values := []float32{1, 2, 3, 4, 5}
+	renderWindow(ui, func() {
+		ui.PlotLines("Lines", values, PlotOptions{Offset: -1})
+		ui.PlotHistogram("Histogram", values, PlotOptions{Offset: -7})
+	})
+}
+
+func TestColorFloats(t *testing.T) {
+	for _, c := range []color.NRGBA{{0, 0, 0, 0}, {255, 128, 7, 255}, {10, 200, 30, 64}, {255, 255, 255, 1}} {
+		if got := floatsToColor(colorToFloats(c)); got != c {
+			t.Errorf("%v came back from imgui's floats as %v", c, got)
+		}
+	}
+	// Premultiplied colors are edited with their full channels.
+	if got, want := floatsToColor(colorToFloats(color.RGBA{R: 128, A: 128})), (color.NRGBA{R: 255, A: 128}); got != want {
+		t.Errorf("premultiplied half transparent red came back as %v, want %v", got, want)
+	}
+	// imgui's editors can go out of gamut, e.g. through HDR or typed values.
+	nan := float32(math.NaN())
+	if got, want := floatsToColor([4]float32{1.5, -0.2, nan, 0.5}), (color.NRGBA{R: 255, A: 128}); got != want {
+		t.Errorf("out of gamut floats became %v, want %v", got, want)
+	}
+
+	ui, _ := newTestUI(t)
+	c := color.NRGBA{R: 10, G: 200, B: 30, A: 64}
+	var got color.Color
+	var changed bool
+	renderWindow(ui, func() {
+		got, changed = ui.ColorEdit("Color", c)
+	})
+	if changed || got != c {
+		t.Errorf("ColorEdit without input returned %v, changed = %v", got, changed)
+	}
+}
+
+func TestInputText(t *testing.T) {
+	long := strings.Repeat("pixel", 100)
+	for _, multiline := range []bool{false, true} {
+		ui, input := newTestUI(t)
+		text := ""
+		changed := false
+		frame := func(focus bool) {
+			ui.NewFrame()
+			imgui.Begin("Test")
+			if focus {
+				imgui.SetKeyboardFocusHere()
+			}
+			if multiline {
+				changed = ui.InputTextMultiline("Field", &text, pixel.ZV, 0)
+			} else {
+				changed = ui.InputText("Field", &text, imgui.InputTextFlagsPassword)
+			}
+			imgui.End()
+			ui.DiscardFrame()
+			input.Update()
+		}
+		// The focus request applies in the next frame, imgui asks for text from the frame after.
+		for i := 0; i < 3; i++ {
+			frame(true)
+		}
+		frame(false)
+		if _, ok := ui.TextInputRect(); !ok {
+			t.Errorf("multiline %v: no text input rect while the field is edited", multiline)
+		}
+
+		// The text is longer than any buffer imgui-go starts with.
+		input.Type(long)
+		frame(false)
+		if !changed || text != long {
+			t.Errorf("multiline %v: typing left %d bytes in the string, changed = %v, want %d", multiline, len(text), changed, len(long))
+		}
+		frame(false)
+		if changed {
+			t.Errorf("multiline %v: the field changed without typing", multiline)
+		}
+	}
+}
+
+func TestTextInputRect(t *testing.T) {
+	ui, input := newTestUI(t)
+	text := "pixel"
+	var field pixel.Rect
+	frame := func(focus, draw bool) {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(200, 100))
+		imgui.Begin("Test")
+		imgui.Text("Name")
+		if focus {
+			imgui.SetKeyboardFocusHere()
+		}
+		if draw {
+			ui.InputText("Field", &text, 0)
+			field = pixel.Rect{Min: ui.ToPixel(PV(imgui.ItemRectMin())), Max: ui.ToPixel(PV(imgui.ItemRectMax()))}.Norm()
+		}
+		imgui.End()
+		ui.DiscardFrame()
+		input.Update()
+	}
+
+	frame(false, true)
+	if _, ok := ui.TextInputRect(); ok {
+		t.Error("TextInputRect reports a field before one is focused")
+	}
+	for i := 0; i < 3; i++ {
+		frame(true, true)
+	}
+	frame(false, true)
+	rect, ok := ui.TextInputRect()
+	if !ok || rect.Area() == 0 {
+		t.Fatalf("TextInputRect = %v, %v while the field is edited", rect, ok)
+	}
+	if rect != field {
+		t.Errorf("TextInputRect = %v, not the field at %v", rect, field)
+	}
+
+	frame(false, false)
+	frame(false, false)
+	if _, ok := ui.TextInputRect(); ok {
+		t.Error("TextInputRect reports a field that isn't drawn anymore")
+	}
+}
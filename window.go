@@ -0,0 +1,318 @@
+package pixelui
+
+import (
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+// SetNextWindowPosPixel sets the top-left corner of the next window to the given Pixel coordinate
+func (ui *UI) SetNextWindowPosPixel(p pixel.Vec, cond imgui.Condition) {
+	imgui.SetNextWindowPosV(IVec(ui.ToImgui(p)), cond, IZV())
+}
+
+// SetNextWindowSizePixel sets the size of the next window, given as a Pixel vector. With a display size
+//
+//	set (see SetDisplaySize) it is scaled into imgui's coordinates like positions are.
+func (ui *UI) SetNextWindowSizePixel(size pixel.Vec, cond imgui.Condition) {
+	imgui.SetNextWindowSizeV(ui.sizeToImgui(size), cond)
+}
+
+// SetNextWindowSizeConstraints limits the size the next window can be resized to, given as Pixel
+//
+//	vectors scaled like SetNextWindowSizePixel. A component of -1 leaves that axis unconstrained (0 for
+//	min, unlimited for max).
+func (ui *UI) SetNextWindowSizeConstraints(min, max pixel.Vec) {
+	imin, imax := ui.sizeToImgui(min), ui.sizeToImgui(max)
+	if min.X == -1 {
+		imin.X = 0
+	}
+	if min.Y == -1 {
+		imin.Y = 0
+	}
+	if max.X == -1 {
+		imax.X = math.MaxFloat32
+	}
+	if max.Y == -1 {
+		imax.Y = math.MaxFloat32
+	}
+	imgui.SetNextWindowSizeConstraints(imin, imax)
+}
+
+// sizeToImgui converts a size in Pixel coordinates to imgui's, which differ with a display size set.
+func (ui *UI) sizeToImgui(size pixel.Vec) imgui.Vec2 {
+	min, max := ui.RectToImgui(pixel.R(0, 0, size.X, size.Y))
+	return max.Minus(min)
+}
+
+// Begin wraps imgui.Begin, giving the window focus if it was requested with FocusWindow and keeping
+//
+//	it inside the work area if enabled with SetWindowsClampToViewport.
+func (ui *UI) Begin(name string) bool {
+	return ui.begin(name, name)
+}
+
+// begin is Begin for a window shown with the given label, e.g. "title###id", keyed by name.
+func (ui *UI) begin(name, label string) bool {
+	if ui.focus != "" && ui.focus == name {
+		imgui.SetNextWindowFocus()
+		ui.focus = ""
+	}
+	if pos, ok := ui.clampPos[name]; ok {
+		imgui.SetNextWindowPos(pos)
+		delete(ui.clampPos, name)
+	}
+
+	open := imgui.Begin(label)
+	if ui.clampWindows {
+		ui.clampWindow(name)
+	}
+	return open
+}
+
+// SetWindowsClampToViewport keeps windows begun with ui.Begin inside the WorkArea, so they can't be
+//
+//	dragged offscreen where they are hard to grab back. A window that ends up outside is moved back
+//	on the next frame.
+func (ui *UI) SetWindowsClampToViewport(enabled bool) {
+	ui.clampWindows = enabled
+	if ui.clampPos == nil {
+		ui.clampPos = make(map[string]imgui.Vec2)
+	}
+}
+
+// clampWindow remembers where to move the current window if it isn't inside the work area.
+func (ui *UI) clampWindow(name string) {
+	pos, size := imgui.WindowPos(), imgui.WindowSize()
+	min := imgui.Vec2{X: 0, Y: ui.menuBarHeight()}
+	max := IVec(ui.displaySize())
+
+	clamp := func(v, min, max float32) float32 {
+		if v > max {
+			v = max
+		}
+		if v < min {
+			v = min
+		}
+		return v
+	}
+	clamped := imgui.Vec2{
+		X: clamp(pos.X, min.X, max.X-size.X),
+		Y: clamp(pos.Y, min.Y, max.Y-size.Y),
+	}
+	if clamped != pos {
+		ui.clampPos[name] = clamped
+	}
+}
+
+// FocusWindow focuses the named window the next time it is begun with ui.Begin
+func (ui *UI) FocusWindow(name string) {
+	ui.focus = name
+}
+
+// SetKeyboardFocusHere focuses the keyboard on the next widget, or the offset-th widget after it
+func (ui *UI) SetKeyboardFocusHere(offset int) {
+	imgui.SetKeyboardFocusHereV(offset)
+}
+
+// Window begins the named window with ui.Begin and runs fn only if it is visible.
+//
+//	imgui.End is always called, even if fn panics, so a failing window can't unbalance imgui's stack.
+func (ui *UI) Window(name string, fn func()) {
+	defer imgui.End()
+	if ui.Begin(name) {
+		fn()
+	}
+}
+
+// WindowWithIcon is Window with the icon drawn in the title bar in front of the title, at the height of
+//
+//	the title text. It assumes imgui's default left aligned title with a collapse button.
+func (ui *UI) WindowWithIcon(name string, icon Image, fn func()) {
+	style := imgui.CurrentStyle()
+	height := ui.fontSize()
+	width := height
+	if icon.size.Y > 0 {
+		width = height * float32(icon.size.X/icon.size.Y)
+	}
+
+	// Make room in front of the title with spaces, keeping the name as the window's id.
+	space := imgui.CalcTextSize(" ", false, 0).X
+	pad := int(math.Ceil(float64((width + style.ItemInnerSpacing().X) / space)))
+	label := strings.Repeat(" ", pad) + name + "###" + name
+
+	defer imgui.End()
+	open := ui.begin(name, label)
+
+	// The title text starts after the frame padding and the collapse button, which is as wide as the font.
+	pos, padding := imgui.WindowPos(), style.FramePadding()
+	min := imgui.Vec2{X: pos.X + padding.X + height + style.ItemInnerSpacing().X, Y: pos.Y + padding.Y}
+	max := imgui.Vec2{X: min.X + width, Y: min.Y + height}
+
+	// The title bar is outside the window's content clip rect.
+	list := imgui.WindowDrawList()
+	list.PushClipRectV(pos, imgui.Vec2{X: pos.X + imgui.WindowSize().X, Y: pos.Y + imgui.FrameHeight()}, false)
+	list.AddImageV(icon.id, min, max, icon.uv0, icon.uv1, imgui.PackedColor(pixelColorToImguiColor(color.White)))
+	list.PopClipRect()
+
+	if open {
+		fn()
+	}
+}
+
+// Child begins a child window and runs fn only if it is visible, always calling imgui.EndChild like Window
+func (ui *UI) Child(id string, fn func()) {
+	defer imgui.EndChild()
+	if imgui.BeginChild(id) {
+		fn()
+	}
+}
+
+// SetNextWindowBgAlpha sets the opacity of the next window's background, e.g. for overlays
+func (ui *UI) SetNextWindowBgAlpha(a float32) {
+	imgui.SetNextWindowBgAlpha(a)
+}
+
+// SetIniFilename sets the file imgui loads and saves its window settings to, imgui.ini in the working
+//
+//	directory by default. An empty name disables the file, e.g. to keep the layout with SaveLayout.
+func (ui *UI) SetIniFilename(name string) {
+	ui.iniFilename = name
+	ui.io.SetIniFilename(name)
+}
+
+// SaveLayout returns imgui's window settings (positions, sizes, collapsed state, ...) in its ini
+//
+//	format and clears WantSaveIniSettings.
+func (ui *UI) SaveLayout() string {
+	ui.savedLayout = imgui.SaveIniSettingsToMemory()
+	return ui.savedLayout
+}
+
+// LoadLayout restores window settings returned by SaveLayout. Call it before the windows are begun.
+func (ui *UI) LoadLayout(layout string) {
+	imgui.LoadIniSettingsFromMemory(layout)
+	ui.savedLayout = imgui.SaveIniSettingsToMemory()
+}
+
+// WantSaveIniSettings returns true if the window settings changed since they were last saved with
+//
+//	SaveLayout or loaded with LoadLayout.
+//	imgui-go doesn't bind io.WantSaveIniSettings, so this serializes the settings and compares them
+//	with the saved ones; check it every few seconds rather than every frame.
+func (ui *UI) WantSaveIniSettings() bool {
+	return imgui.SaveIniSettingsToMemory() != ui.savedLayout
+}
+
+// ClearWantSaveIniSettings resets WantSaveIniSettings, e.g. after saving the layout some other way
+func (ui *UI) ClearWantSaveIniSettings() {
+	ui.savedLayout = imgui.SaveIniSettingsToMemory()
+}
+
+// MainMenuBar draws a menu bar across the top of the display, running fn to add its menus when it is
+//
+//	visible. The space it takes is left out of WorkArea.
+func (ui *UI) MainMenuBar(fn func()) {
+	if !imgui.BeginMainMenuBar() {
+		return
+	}
+	defer imgui.EndMainMenuBar()
+	ui.menuBar[0] = imgui.WindowHeight()
+	fn()
+}
+
+// WorkArea returns the part of the display not covered by the main menu bar, in Pixel coordinates
+//
+//	Before the menu bar is drawn in a frame, the previous frame's menu bar is left out.
+func (ui *UI) WorkArea() pixel.Rect {
+	min := ui.ToPixel(pixel.V(0, float64(ui.menuBarHeight())))
+	max := ui.ToPixel(ui.displaySize())
+	return pixel.Rect{Min: min, Max: max}.Norm()
+}
+
+// menuBarHeight returns the height of the main menu bar this frame, or the last one if not drawn yet.
+func (ui *UI) menuBarHeight() float32 {
+	if ui.menuBar[0] != 0 {
+		return ui.menuBar[0]
+	}
+	return ui.menuBar[1]
+}
+
+// SmoothScrollToBottom scrolls the current window towards its bottom, e.g. for a log that follows new
+//
+//	lines, instead of jumping there. speed is how quickly the distance left closes, per second; the
+//	higher, the snappier. Call it after the window's content.
+func (ui *UI) SmoothScrollToBottom(speed float32) {
+	y, max := imgui.ScrollY(), imgui.ScrollMaxY()
+	if y >= max {
+		return
+	}
+
+	step := speed * ui.delta
+	if step > 1 {
+		step = 1
+	}
+	y += (max - y) * step
+	// imgui floors scroll positions to whole pixels, the last one has to be taken in one go.
+	if max-y < 1 {
+		y = max
+	}
+	imgui.SetScrollY(y)
+}
+
+// OpenPopupAt opens the popup with the given id with its top-left corner at a Pixel coordinate.
+//
+//	The position is applied when the popup is next begun with ui.Popup or ui.Modal.
+func (ui *UI) OpenPopupAt(id string, pos pixel.Vec) {
+	if ui.popupPos == nil {
+		ui.popupPos = make(map[string]popupAt)
+	}
+	ui.popupPos[id] = popupAt{pos: IVec(ui.ToImgui(pos))}
+	imgui.OpenPopup(id)
+}
+
+// popupAt is where a popup opened with OpenPopupAt goes and the frame it was first begun in.
+type popupAt struct {
+	pos   imgui.Vec2
+	frame int
+}
+
+// Popup runs fn while the popup with the given id is open, always balancing imgui.EndPopup
+func (ui *UI) Popup(id string, fn func()) {
+	ui.popupPosition(id)
+	if !imgui.BeginPopup(id) {
+		return
+	}
+	defer imgui.EndPopup()
+	fn()
+}
+
+// Modal runs fn while the modal popup with the given id is open, always balancing imgui.EndPopup
+func (ui *UI) Modal(id string, fn func()) {
+	ui.popupPosition(id)
+	if !imgui.BeginPopupModal(id) {
+		return
+	}
+	defer imgui.EndPopup()
+	fn()
+}
+
+// popupPosition positions the popup about to be begun if it was opened with OpenPopupAt.
+func (ui *UI) popupPosition(id string) {
+	at, ok := ui.popupPos[id]
+	if !ok {
+		return
+	}
+	imgui.SetNextWindowPos(at.pos)
+	// imgui hides a new popup for a frame to fit its size, then places it next to where it was opened
+	//	unless its position is set again, so hold it through the frame after.
+	if at.frame == 0 {
+		at.frame = ui.frames
+		ui.popupPos[id] = at
+	} else if ui.frames > at.frame {
+		delete(ui.popupPos, id)
+	}
+}
@@ -0,0 +1,357 @@
+package pixelui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/inkyblackness/imgui-go/v4"
+)
+
+func TestSetNextWindowSizePixel(t *testing.T) {
+	tests := []struct {
+		name    string
+		display pixel.Vec
+		want    imgui.Vec2
+	}{
+		{"window size", pixel.ZV, IV(100, 50)},
+		// The 200x100 window shows a 400x400 display, a pixel is 2x4 imgui units.
+		{"display size", pixel.V(400, 400), IV(200, 200)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ui, _ := newTestUI(t)
+			ui.SetDisplaySize(tt.display)
+			ui.NewFrame()
+			defer ui.DiscardFrame()
+
+			ui.SetNextWindowSizePixel(pixel.V(100, 50), imgui.ConditionAlways)
+			imgui.Begin("Test")
+			got := imgui.WindowSize()
+			imgui.End()
+			if got != tt.want {
+				t.Errorf("window size = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetNextWindowSizeConstraints(t *testing.T) {
+	ui, _ := newTestUI(t)
+	// The 200x100 window shows a 400x400 display, a pixel is 2x4 imgui units.
+	ui.SetDisplaySize(pixel.V(400, 400))
+	ui.NewFrame()
+	defer ui.DiscardFrame()
+
+	tests := []struct {
+		name     string
+		min, max pixel.Vec
+		size     imgui.Vec2
+		want     imgui.Vec2
+	}{
+		{"below min", pixel.V(50, 25), pixel.V(-1, -1), IV(10, 10), IV(100, 100)},
+		{"above max", pixel.V(-1, -1), pixel.V(-1, 60), IV(1000, 1000), IV(1000, 240)},
+	}
+	for _, tt := range tests {
+		ui.SetNextWindowSizeConstraints(tt.min, tt.max)
+		imgui.SetNextWindowSize(tt.size)
+		imgui.Begin(tt.name)
+		got := imgui.WindowSize()
+		imgui.End()
+		if got != tt.want {
+			t.Errorf("%s: window size = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWindowEndsOnPanic(t *testing.T) {
+	ui, _ := newTestUI(t)
+	ui.NewFrame()
+	ran := false
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want the panic of fn", r)
+			}
+		}()
+		ui.Window("Test", func() {
+			ran = true
+			panic("boom")
+		})
+	}()
+	if !ran {
+		t.Fatal("the window's fn didn't run")
+	}
+
+	// imgui asserts on an unbalanced Begin when the frame is rendered or the next one starts.
+	imgui.Render()
+	ui.inFrame = false
+	ui.NewFrame()
+	ui.Window("Test", func() {})
+	ui.DiscardFrame()
+}
+
+func TestSetNextWindowBgAlpha(t *testing.T) {
+	ui, _ := newTestUI(t)
+	// background returns the alpha of the magenta background with the given bg alpha, or without
+	//	setting it if negative. Red and blue are equal, so it reads the same with the pixelui_bgra tag.
+	background := func(alpha float32) (uint8, bool) {
+		for i := 0; i < 2; i++ {
+			ui.NewFrame()
+			imgui.PushStyleColor(imgui.StyleColorWindowBg, imgui.Vec4{X: 1, Y: 0, Z: 1, W: 1})
+			if alpha >= 0 {
+				ui.SetNextWindowBgAlpha(alpha)
+			}
+			imgui.Begin("Test")
+			imgui.End()
+			imgui.PopStyleColor()
+			imgui.Render()
+			ui.inFrame = false
+		}
+		for _, batch := range ui.DrawDataSlices() {
+			for _, c := range batch.Colors {
+				if c.R == 255 && c.G == 0 && c.B == 255 {
+					return c.A, true
+				}
+			}
+		}
+		return 0, false
+	}
+
+	if a, ok := background(-1); !ok || a != 255 {
+		t.Fatalf("the opaque magenta background has alpha %d, found = %v", a, ok)
+	}
+	if a, ok := background(0.5); !ok || a < 126 || a > 129 {
+		t.Errorf("the background has alpha %d with a bg alpha of 0.5, found = %v", a, ok)
+	}
+}
+
+func TestWantSaveIniSettings(t *testing.T) {
+	ui, _ := newTestUI(t)
+	frame := func(pos imgui.Vec2) {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(pos)
+		imgui.SetNextWindowSize(IV(100, 50))
+		imgui.Begin("Test")
+		imgui.End()
+		imgui.Render()
+		ui.inFrame = false
+	}
+	frame(IV(0, 0))
+	layout := ui.SaveLayout()
+	if ui.WantSaveIniSettings() {
+		t.Fatal("WantSaveIniSettings is true right after saving")
+	}
+
+	frame(IV(40, 20))
+	if !ui.WantSaveIniSettings() {
+		t.Fatal("WantSaveIniSettings is false after moving the window")
+	}
+	if ui.SaveLayout() == layout {
+		t.Error("the saved layout didn't change after moving the window")
+	}
+	if ui.WantSaveIniSettings() {
+		t.Error("WantSaveIniSettings is true after saving the moved window")
+	}
+
+	frame(IV(0, 0))
+	ui.ClearWantSaveIniSettings()
+	if ui.WantSaveIniSettings() {
+		t.Error("WantSaveIniSettings is true after clearing it")
+	}
+}
+
+func TestMainMenuBar(t *testing.T) {
+	ui, _ := newTestUI(t)
+	var ran int
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		ui.MainMenuBar(func() {
+			ran++
+			if imgui.BeginMenu("View") {
+				imgui.MenuItem("Fullscreen")
+				imgui.EndMenu()
+			}
+		})
+		// An unbalanced menu bar would leave this window nested in it, which Render asserts against.
+		imgui.Begin("Test")
+		imgui.End()
+		imgui.Render()
+		ui.inFrame = false
+	}
+
+	if ran != 2 {
+		t.Errorf("the menu bar ran its menus %d times in 2 frames", ran)
+	}
+	height := float64(imgui.TextLineHeight())
+	if area := ui.WorkArea(); area.Min != pixel.ZV || area.Max.X != 200 || area.Max.Y > 100-height {
+		t.Errorf("work area %v reaches into the %v px menu bar at the top of the 200x100 display", area, height)
+	}
+}
+
+func TestWindowsClampToViewport(t *testing.T) {
+	ui, input := newTestUI(t)
+	ui.SetWindowsClampToViewport(true)
+	var pos imgui.Vec2
+	frame := func() {
+		ui.NewFrame()
+		imgui.SetNextWindowPosV(IV(20, 20), imgui.ConditionFirstUseEver, imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(80, 40))
+		ui.Begin("Test")
+		pos = imgui.WindowPos()
+		imgui.End()
+		ui.DiscardFrame()
+		input.Update()
+	}
+
+	// Drag the title bar towards the bottom-right corner, which would leave most of the window offscreen.
+	input.MoveMouse(pixel.V(30, 75))
+	frame()
+	frame()
+	input.Press(pixel.MouseButtonLeft)
+	frame()
+	input.MoveMouse(pixel.V(190, 5))
+	frame()
+	frame()
+	input.Release(pixel.MouseButtonLeft)
+	frame()
+	frame()
+
+	if pos != IV(120, 60) {
+		t.Errorf("the window dragged offscreen is at %v, want clamped to (120, 60)", pos)
+	}
+}
+
+func TestSmoothScrollToBottom(t *testing.T) {
+	ui, input := newTestUI(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ui.SetTimeSource(func() time.Time {
+		now = now.Add(100 * time.Millisecond)
+		return now
+	})
+	lines := 10
+	var y, max float32
+	frame := func() {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(imgui.Vec2{})
+		imgui.SetNextWindowSize(IV(200, 100))
+		imgui.Begin("Log")
+		for i := 0; i < lines; i++ {
+			imgui.Text("Line")
+		}
+		y, max = imgui.ScrollY(), imgui.ScrollMaxY()
+		// Half the distance left each 100 ms frame.
+		ui.SmoothScrollToBottom(5)
+		imgui.End()
+		ui.DiscardFrame()
+		input.Update()
+	}
+
+	gradual := false
+	last := float32(0)
+	for i := 0; i < 10; i++ {
+		lines += 2
+		frame()
+		if y < last {
+			t.Fatalf("frame %d scrolled back up from %v to %v", i, last, y)
+		}
+		gradual = gradual || (y > last && y < max)
+		last = y
+	}
+	if !gradual {
+		t.Error("the log jumped to the bottom instead of scrolling there gradually")
+	}
+
+	// Once no more lines come in, it settles at the bottom.
+	for i := 0; i < 15; i++ {
+		frame()
+	}
+	if max == 0 || y != max {
+		t.Errorf("scrolled to %v of %v after the log stopped growing", y, max)
+	}
+}
+
+func TestOpenPopupAt(t *testing.T) {
+	for _, modal := range []bool{false, true} {
+		ui, input := newTestUI(t)
+		begin := ui.Popup
+		if modal {
+			begin = ui.Modal
+		}
+		var pos imgui.Vec2
+		open := false
+		frame := func(openAt bool) {
+			ui.NewFrame()
+			if openAt {
+				ui.OpenPopupAt("Popup", pixel.V(50, 70))
+			}
+			begin("Popup", func() {
+				open = true
+				pos = imgui.WindowPos()
+				imgui.Text("Item")
+			})
+			ui.DiscardFrame()
+			input.Update()
+		}
+
+		// imgui hides the popup in its first frame to fit it, then would place it next to where it was
+		//	opened.
+		frame(true)
+		frame(false)
+		frame(false)
+
+		// 70 up from the bottom of the 100 high window is 30 down from its top.
+		if !open || pos != IV(50, 30) {
+			t.Errorf("modal %v: popup open = %v at %v, want open at (50, 30)", modal, open, pos)
+		}
+	}
+}
+
+func TestWindowWithIcon(t *testing.T) {
+	ui, input := newTestUI(t)
+	icon := ui.AddImage(testPicture(16, 16, 1))
+	ran := false
+	var titleBar pixel.Rect
+	// New windows are hidden in their first frame.
+	for i := 0; i < 2; i++ {
+		ui.NewFrame()
+		imgui.SetNextWindowPos(IV(10, 10))
+		imgui.SetNextWindowSize(IV(150, 80))
+		ui.WindowWithIcon("Tools", icon, func() {
+			ran = true
+			imgui.Text("Content")
+		})
+		titleBar = pixel.R(10, 10, 160, 10+float64(imgui.FrameHeight()))
+		imgui.Render()
+		ui.inFrame = false
+		input.Update()
+	}
+	if !ran {
+		t.Error("the window's content didn't run")
+	}
+
+	var quad pixel.Rect
+	vertices := 0
+	for _, batch := range ui.DrawDataSlices() {
+		if batch.TextureID != icon.ID() {
+			continue
+		}
+		for _, p := range batch.Positions {
+			if vertices == 0 {
+				quad = pixel.Rect{Min: p, Max: p}
+			}
+			quad = quad.Union(pixel.Rect{Min: p, Max: p})
+			vertices++
+		}
+	}
+	if vertices != 6 {
+		t.Fatalf("the icon has %d vertices, want one quad", vertices)
+	}
+	if quad.Intersect(titleBar) != quad || quad.Area() == 0 {
+		t.Errorf("the icon quad %v isn't inside the title bar %v", quad, titleBar)
+	}
+	// The collapse arrow comes first, as wide as the font.
+	if arrow := 10 + float64(imgui.CurrentStyle().FramePadding().X+imgui.FontSize()); quad.Min.X < arrow {
+		t.Errorf("the icon quad %v overlaps the collapse arrow ending at x %v", quad, arrow)
+	}
+}